@@ -0,0 +1,86 @@
+package i3bar
+
+import (
+	"sync"
+)
+
+// ScrollAdjuster binds scrolling on a block to a bounded value,
+// the common pattern of volume and brightness blocks.
+// Scrolling up increments and scrolling down decrements the value by step.
+type ScrollAdjuster struct {
+	mux   sync.Mutex
+	value int
+	step  int
+	min   int
+	max   int
+	apply func(value int)
+}
+
+// NewScrollAdjuster creates a ScrollAdjuster for value within min and max.
+// apply is called with the new value whenever scrolling changed it.
+func NewScrollAdjuster(value, step, min, max int, apply func(value int)) *ScrollAdjuster {
+	a := &ScrollAdjuster{step: step, min: min, max: max, apply: apply}
+	a.value = a.clamp(value)
+	return a
+}
+
+// Value returns the current value.
+func (a *ScrollAdjuster) Value() int {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return a.value
+}
+
+// Set updates the value without calling apply,
+// e.g. if the value got changed by another program.
+func (a *ScrollAdjuster) Set(value int) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.value = a.clamp(value)
+}
+
+// Handle adjusts the value on scroll events and ignores all other click events.
+// Scroll events coalesced by WithScrollDebounce adjust the value once per event.
+// Handle can be registered as click handler, e.g. with Stream.OnClick.
+func (a *ScrollAdjuster) Handle(ev ClickEvent) {
+	steps := ev.Delta
+	if steps < 1 {
+		steps = 1
+	}
+	switch ev.Button {
+	case ScrollUp:
+	case ScrollDown:
+		steps = -steps
+	default:
+		return
+	}
+
+	a.update(func(value int) int {
+		return value + steps*a.step
+	})
+}
+
+// update sets the value to the result of fn
+// and calls apply if the value changed.
+func (a *ScrollAdjuster) update(fn func(value int) int) {
+	a.mux.Lock()
+	value := a.clamp(fn(a.value))
+	changed := value != a.value
+	a.value = value
+	a.mux.Unlock()
+
+	if changed && a.apply != nil {
+		a.apply(value)
+	}
+}
+
+// clamp limits value to the bounds of the ScrollAdjuster.
+func (a *ScrollAdjuster) clamp(value int) int {
+	if value < a.min {
+		return a.min
+	}
+	if value > a.max {
+		return a.max
+	}
+	return value
+}
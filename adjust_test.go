@@ -0,0 +1,57 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScrollAdjuster(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   int
+		events  []ClickEvent
+		want    int
+		applied []int
+	}{
+		{"up", 50, []ClickEvent{{Button: ScrollUp}}, 55, []int{55}},
+		{"down", 50, []ClickEvent{{Button: ScrollDown}, {Button: ScrollDown}}, 40, []int{45, 40}},
+		{"coalesced", 50, []ClickEvent{{Button: ScrollUp, Delta: 3}}, 65, []int{65}},
+		{"clamped", 98, []ClickEvent{{Button: ScrollUp}, {Button: ScrollUp}}, 100, []int{100}},
+		{"at minimum", 0, []ClickEvent{{Button: ScrollDown}}, 0, nil},
+		{"other buttons", 50, []ClickEvent{{Button: LeftClick}, {Button: RightClick}}, 50, nil},
+		{"initial value clamped", 120, nil, 100, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied []int
+			a := NewScrollAdjuster(tt.value, 5, 0, 100, func(value int) {
+				applied = append(applied, value)
+			})
+			for _, ev := range tt.events {
+				a.Handle(ev)
+			}
+			if got := a.Value(); got != tt.want {
+				t.Errorf("Value() = %d, want %d", got, tt.want)
+			}
+			if !reflect.DeepEqual(applied, tt.applied) {
+				t.Errorf("applied %v, want %v", applied, tt.applied)
+			}
+		})
+	}
+}
+
+func TestScrollAdjusterSet(t *testing.T) {
+	applied := 0
+	a := NewScrollAdjuster(50, 5, 0, 100, func(int) { applied++ })
+	a.Set(-10)
+	if a.Value() != 0 || applied != 0 {
+		t.Errorf("got value %d and %d applies, want 0 and 0", a.Value(), applied)
+	}
+
+	// apply is optional
+	a = NewScrollAdjuster(50, 5, 0, 100, nil)
+	a.Handle(ClickEvent{Button: ScrollUp})
+	if a.Value() != 55 {
+		t.Errorf("Value() = %d, want 55", a.Value())
+	}
+}
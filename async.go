@@ -0,0 +1,79 @@
+package i3bar
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sendAsync stores a copy of the status line as latest state
+// and wakes up the asynchronous writer.
+// The status line is encoded by the writer, so that lazy texts
+// of superseded status lines are never evaluated.
+func (s *Stream) sendAsync(b StatusLine) error {
+	select {
+	case <-s.stopped():
+		return ErrClosed
+	default:
+	}
+
+	b, err := s.prepare(b)
+	if err != nil {
+		return errors.Wrap(err, "Failed to send status line")
+	}
+	b = b.Clone()
+
+	s.aMux.Lock()
+	s.latest = b
+	s.aMux.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// writeAsync writes the latest status line whenever it changes,
+// but at most once per asyncInterval, until done is closed.
+func (s *Stream) writeAsync(done <-chan struct{}) {
+	for {
+		select {
+		case <-s.notify:
+		case <-done:
+			return
+		}
+
+		var err error
+		s.wMux.Lock()
+		// the latest status line is kept until the stream is resumed
+		if !s.closed && !s.paused {
+			err = s.flushAsync()
+		}
+		s.wMux.Unlock()
+		if err != nil {
+			s.reportError(errors.Wrap(err, "Failed to send status line"))
+		}
+
+		select {
+		case <-time.After(s.asyncInterval):
+		case <-done:
+			return
+		}
+	}
+}
+
+// flushAsync writes the latest status line if there is any,
+// regardless of the stream being paused.
+// The caller has to hold wMux.
+func (s *Stream) flushAsync() error {
+	s.aMux.Lock()
+	b := s.latest
+	s.latest = nil
+	s.aMux.Unlock()
+
+	if b == nil {
+		return nil
+	}
+	return s.encodeLine(b)
+}
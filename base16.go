@@ -0,0 +1,88 @@
+package i3bar
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadBase16 reads a base16 scheme in YAML format from r and creates a Theme.
+//
+// The colors are mapped as follows:
+// base00 is the background, base05 the foreground, base02 the border,
+// base08 (red) is bad, base0A (yellow) is degraded and base0B (green) is good.
+// Urgent blocks use base00 on base08.
+func LoadBase16(r io.Reader) (*Theme, error) {
+	values, err := parseFlatYAML(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read base16 scheme")
+	}
+
+	colors := map[string]Color{}
+	for _, key := range []string{"base00", "base02", "base05", "base08", "base0A", "base0B"} {
+		value, ok := values[strings.ToLower(key)]
+		if !ok {
+			return nil, errors.Errorf("base16 scheme is missing %s", key)
+		}
+		c, err := ParseColor("#" + strings.TrimPrefix(value, "#"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid base16 color %s", key)
+		}
+		colors[key] = c
+	}
+
+	return &Theme{
+		Foreground:       colors["base05"],
+		Background:       colors["base00"],
+		Border:           colors["base02"],
+		UrgentForeground: colors["base00"],
+		UrgentBackground: colors["base08"],
+		Good:             colors["base0B"],
+		Degraded:         colors["base0A"],
+		Bad:              colors["base08"],
+	}, nil
+}
+
+// LoadBase16File reads a base16 scheme from the YAML file at path and creates a Theme.
+func LoadBase16File(path string) (*Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open base16 scheme")
+	}
+	defer f.Close()
+	return LoadBase16(f)
+}
+
+// parseFlatYAML parses the flat "key: value" mappings used by base16 schemes.
+// Keys are lower cased and quotes around values are removed.
+func parseFlatYAML(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid line: %s", line)
+		}
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+				value = value[1 : end+1]
+			}
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			// strip trailing comment
+			value = strings.TrimSpace(value[:i])
+		}
+		values[strings.ToLower(strings.TrimSpace(parts[0]))] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package i3bar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const base16Scheme = `---
+# comment
+scheme: "Test"
+author: 'someone'
+base00: "101010"
+base01: "202020"
+base02: '303030'
+base03: "404040"
+base04: "505050"
+Base05: #e0e0e0 # unquoted with trailing comment
+base06: "f0f0f0"
+base07: "ffffff"
+base08: "ff0000"
+base09: "ff8000"
+base0A: "FFFF00"
+base0B: "00ff00"
+`
+
+var base16Theme = &Theme{
+	Foreground:       "#e0e0e0",
+	Background:       "#101010",
+	Border:           "#303030",
+	UrgentForeground: "#101010",
+	UrgentBackground: "#ff0000",
+	Good:             "#00ff00",
+	Degraded:         "#ffff00",
+	Bad:              "#ff0000",
+}
+
+func TestLoadBase16(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme string
+		want   *Theme
+		err    string
+	}{
+		{"valid", base16Scheme, base16Theme, ""},
+		{"missing color", strings.Replace(base16Scheme, "base0B", "base0C", 1), nil, "missing base0B"},
+		{"invalid color", strings.Replace(base16Scheme, `"ff0000"`, `"xyz"`, 1), nil, "invalid base16 color base08"},
+		{"invalid line", base16Scheme + "no mapping\n", nil, "invalid line"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadBase16(strings.NewReader(tt.scheme))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBase16File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheme.yaml")
+	if err := os.WriteFile(path, []byte(base16Scheme), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadBase16File(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, base16Theme) {
+		t.Errorf("got %+v, want %+v", got, base16Theme)
+	}
+
+	if _, err := LoadBase16File(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loading missing scheme succeeded")
+	}
+}
@@ -0,0 +1,114 @@
+package i3bar
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// blinkBlocks remembers the status line for blinking if it contains
+// urgent blocks and returns it rendered in the current blink phase.
+// The caller has to hold wMux.
+func (s *Stream) blinkBlocks(b StatusLine) StatusLine {
+	if s.blinkInterval <= 0 {
+		return b
+	}
+	if !hasUrgent(b) {
+		s.blinkLine, s.blinkOff = nil, false
+		return b
+	}
+	if !s.blinking {
+		// the line is encoded again on every blink, so its lazy texts
+		// are evaluated once here instead of on every blink
+		s.blinkLine = resolveLazyTexts(b)
+		b = s.blinkLine
+	}
+	if !s.blinkOff {
+		return b
+	}
+
+	out := make(StatusLine, len(b))
+	for i, block := range b {
+		if block == nil || !block.Urgent {
+			out[i] = block
+			continue
+		}
+		off := *block
+		off.Urgent = false
+		if s.blinkColor != "" {
+			off.Color = s.blinkColor
+		}
+		if s.blinkBackground != "" {
+			off.Background = s.blinkBackground
+		}
+		if s.target != Swaybar {
+			off.Color = off.Color.WithoutAlpha()
+			off.Background = off.Background.WithoutAlpha()
+		}
+		out[i] = &off
+	}
+	return out
+}
+
+// resolveLazyTexts returns a copy of b whose blocks carry their
+// evaluated lazy texts as FullText.
+func resolveLazyTexts(b StatusLine) StatusLine {
+	out := b.Clone()
+	for _, block := range out {
+		if block != nil && block.LazyText != nil {
+			block.FullText, block.LazyText = block.Text(), nil
+		}
+	}
+	return out
+}
+
+// hasUrgent reports whether any block of the status line is urgent.
+func hasUrgent(b StatusLine) bool {
+	for _, block := range b {
+		if block != nil && block.Urgent {
+			return true
+		}
+	}
+	return false
+}
+
+// blink toggles the blink phase and sends the last status line
+// again every blinkInterval while it contains urgent blocks,
+// until done is closed. Blink frames are throttled like status lines
+// sent by SendLine, see WithMinInterval.
+func (s *Stream) blink(done <-chan struct{}) {
+	ticks, stop := s.newTicker(s.blinkInterval)
+	defer stop()
+	for {
+		select {
+		case <-ticks:
+		case <-done:
+			return
+		}
+
+		var err error
+		s.wMux.Lock()
+		// a pending status line supersedes the blinking one
+		if !s.closed && !s.paused && s.pending == nil && s.blinkLine != nil {
+			s.blinkOff = !s.blinkOff
+			if s.throttle(s.blinkLine) {
+				s.pendingBlink = true
+			} else {
+				s.blinking = true
+				err = s.encodeLine(s.blinkLine)
+				s.blinking = false
+			}
+		}
+		s.wMux.Unlock()
+		if err != nil {
+			s.reportError(errors.Wrap(err, "Failed to send blinking status line"))
+		}
+	}
+}
+
+// newTicker starts a time.Ticker and returns its channel
+// and a function stopping it.
+func newTicker(d time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(d)
+	return ticker.C, ticker.Stop
+}
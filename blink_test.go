@@ -0,0 +1,131 @@
+package i3bar
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mux sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return b.buf.String()
+}
+
+// withTicks makes the stream blink on every tick sent to ticks
+// instead of using a time.Ticker.
+func withTicks(ticks chan time.Time) Option {
+	return func(s *Stream) {
+		s.newTicker = func(time.Duration) (<-chan time.Time, func()) {
+			return ticks, func() {}
+		}
+	}
+}
+
+// statusLines returns the status lines written to out without the header.
+func statusLines(out string) []string {
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	return lines[1:]
+}
+
+func TestBlink(t *testing.T) {
+	var out, plain lockedBuffer
+	ticks := make(chan time.Time)
+	s, err := NewStream(&out, nil, false, DefaultHeader(),
+		WithBlink(time.Second, "#000000", ""),
+		WithPlainText(&plain),
+		withTicks(ticks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	evaluated := 0
+	lazy := TextFunc(func() string {
+		evaluated++
+		return "a"
+	})
+	if err := s.SendLine(StatusLine{{LazyText: lazy, Urgent: true}, {FullText: "b"}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		ticks <- time.Time{}
+	}
+	waitFor(t, "blink frames", func() bool { return len(statusLines(out.String())) == 4 })
+
+	on := `[{"full_text":"a","urgent":true},{"full_text":"b"}]`
+	off := `[{"full_text":"a","color":"#000000"},{"full_text":"b"}]`
+	want := []string{"[" + on, "," + off, "," + on, "," + off}
+	if got := statusLines(out.String()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got status lines:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if evaluated != 1 {
+		t.Errorf("lazy text evaluated %d times, want once", evaluated)
+	}
+	if got := plain.String(); got != "a | b\n" {
+		t.Errorf("blink frames written as plain text: %q", got)
+	}
+
+	// blinking stops once no block is urgent anymore
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	if got := statusLines(out.String()); len(got) != 5 {
+		t.Errorf("sent %d status lines after urgency ended, want 5", len(got))
+	}
+}
+
+func TestBlinkMinInterval(t *testing.T) {
+	var out, plain lockedBuffer
+	ticks := make(chan time.Time)
+	s, err := NewStream(&out, nil, false, DefaultHeader(),
+		WithBlink(time.Second, "#000000", ""),
+		WithMinInterval(time.Hour),
+		WithPlainText(&plain),
+		withTicks(ticks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.SendLine(StatusLine{{FullText: "a", Urgent: true}}); err != nil {
+		t.Fatal(err)
+	}
+	// the frames are held back by the minimum interval and the
+	// second tick returns once the first has been handled
+	for i := 0; i < 4; i++ {
+		ticks <- time.Time{}
+	}
+	if got := statusLines(out.String()); len(got) != 1 {
+		t.Errorf("sent %d status lines within the minimum interval, want 1", len(got))
+	}
+
+	// the latest held back frame is sent on close
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`[[{"full_text":"a","urgent":true}]`, `,[{"full_text":"a","color":"#000000"}]`, "]"}
+	if got := statusLines(out.String()); !reflect.DeepEqual(got, want) {
+		t.Errorf("got status lines %q, want %q", got, want)
+	}
+	if got := plain.String(); got != "a\n" {
+		t.Errorf("blink frames written as plain text: %q", got)
+	}
+}
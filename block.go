@@ -0,0 +1,167 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// block is used to encode and decode Block without
+// recursing into Block.MarshalJSON and Block.UnmarshalJSON.
+type block Block
+
+// blockKeys contains the json keys of all regular Block fields.
+var blockKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(Block{})
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if key != "" && key != "-" {
+			keys[key] = true
+		}
+	}
+	return keys
+}()
+
+// TextFunc adapts a closure to fmt.Stringer for Block.LazyText.
+type TextFunc func() string
+
+// String calls f.
+func (f TextFunc) String() string {
+	return f()
+}
+
+// Text returns the FullText of the Block
+// or evaluates LazyText if FullText is empty.
+func (b *Block) Text() string {
+	if b.FullText == "" && b.LazyText != nil {
+		return b.LazyText.String()
+	}
+	return b.FullText
+}
+
+// MarshalJSON encodes the Block, evaluates LazyText and merges its Extra keys.
+func (b Block) MarshalJSON() ([]byte, error) {
+	b.FullText = b.Text()
+	data, err := json.Marshal(block(b))
+	if err != nil || len(b.Extra) == 0 {
+		return data, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, errors.Wrap(err, "Failed to merge extra keys")
+	}
+	for key, value := range b.Extra {
+		if _, ok := fields[key]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to encode extra key %s", key)
+		}
+		fields[key] = raw
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes the Block and collects
+// all unknown keys in Extra.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	var decoded block
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return errors.Wrap(err, "Failed to decode block")
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return errors.Wrap(err, "Failed to decode block")
+	}
+	for key, value := range fields {
+		if blockKeys[key] {
+			continue
+		}
+		if decoded.Extra == nil {
+			decoded.Extra = map[string]interface{}{}
+		}
+		decoded.Extra[key] = value
+	}
+
+	*b = Block(decoded)
+	return nil
+}
+
+// Clone returns a deep copy of the Block.
+// Nested maps and slices within Extra are copied as well.
+func (b *Block) Clone() *Block {
+	if b == nil {
+		return nil
+	}
+	c := *b
+	if b.MinWidth != nil {
+		mw := *b.MinWidth
+		c.MinWidth = &mw
+	}
+	c.Separator = cloneBool(b.Separator)
+	c.BorderTop = cloneInt(b.BorderTop)
+	c.BorderRight = cloneInt(b.BorderRight)
+	c.BorderBottom = cloneInt(b.BorderBottom)
+	c.BorderLeft = cloneInt(b.BorderLeft)
+	if b.Extra != nil {
+		c.Extra = cloneValue(b.Extra).(map[string]interface{})
+	}
+	return &c
+}
+
+// Clone returns a deep copy of the StatusLine and all of its blocks.
+func (l StatusLine) Clone() StatusLine {
+	if l == nil {
+		return nil
+	}
+	c := make(StatusLine, len(l))
+	for i, b := range l {
+		c[i] = b.Clone()
+	}
+	return c
+}
+
+// cloneBool copies an optional boolean.
+func cloneBool(v *bool) *bool {
+	if v == nil {
+		return nil
+	}
+	return Bool(*v)
+}
+
+// cloneInt copies an optional integer.
+func cloneInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	return Int(*v)
+}
+
+// cloneValue deep copies maps and slices as produced by encoding/json.
+// Any other value is returned as is.
+func cloneValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			c[key] = cloneValue(value)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, value := range v {
+			c[i] = cloneValue(value)
+		}
+		return c
+	case json.RawMessage:
+		return append(json.RawMessage(nil), v...)
+	default:
+		return v
+	}
+}
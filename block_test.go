@@ -0,0 +1,235 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMinWidthJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		minWidth *MinWidth
+		json     string
+	}{
+		{"pixels", MinWidthPixels(30), `30`},
+		{"zero pixels", MinWidthPixels(0), `0`},
+		{"text", MinWidthText("100%"), `"100%"`},
+		{"text with quotes", MinWidthText(`"x"`), `"\"x\""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.minWidth)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("got %s, want %s", data, tt.json)
+			}
+
+			var decoded MinWidth
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded != *tt.minWidth {
+				t.Errorf("decoded %+v, want %+v", decoded, *tt.minWidth)
+			}
+		})
+	}
+
+	// the text takes precedence over the pixels
+	if data, _ := json.Marshal(MinWidth{Pixels: 30, Text: "abc"}); string(data) != `"abc"` {
+		t.Errorf("got %s, want the text", data)
+	}
+	// min_width is omitted from blocks without it
+	if data, _ := json.Marshal(&Block{FullText: "a"}); string(data) != `{"full_text":"a"}` {
+		t.Errorf("got %s without min_width", data)
+	}
+}
+
+func TestMinWidthInvalid(t *testing.T) {
+	for _, data := range []string{`true`, `1.5`, `{"pixels":1}`, `[1]`} {
+		var m MinWidth
+		if err := json.Unmarshal([]byte(data), &m); err == nil {
+			t.Errorf("decoding %s succeeded: %+v", data, m)
+		}
+	}
+}
+
+func TestSeparatorJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		separator *bool
+		json      string
+	}{
+		// i3bar draws a separator unless it is disabled explicitly
+		{"unset", nil, `{"full_text":"a"}`},
+		{"true", Bool(true), `{"full_text":"a","separator":true}`},
+		{"false", Bool(false), `{"full_text":"a","separator":false}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(&Block{FullText: "a", Separator: tt.separator})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("got %s, want %s", data, tt.json)
+			}
+
+			var decoded Block
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if (decoded.Separator == nil) != (tt.separator == nil) ||
+				(tt.separator != nil && *decoded.Separator != *tt.separator) {
+				t.Errorf("decoded separator %v, want %v", decoded.Separator, tt.separator)
+			}
+		})
+	}
+
+	// clones do not share the separator
+	b := &Block{Separator: Bool(true)}
+	c := b.Clone()
+	*c.Separator = false
+	if !*b.Separator {
+		t.Error("changing the separator of a clone changed the original")
+	}
+}
+
+func TestBlockExtra(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		json  string
+	}{
+		{"no extra", Block{FullText: "a"}, `{"full_text":"a"}`},
+		{"extra keys", Block{FullText: "a", Extra: map[string]interface{}{"_id": 1, "_tags": []string{"x"}}}, `{"_id":1,"_tags":["x"],"full_text":"a"}`},
+		{"regular field wins", Block{FullText: "a", Extra: map[string]interface{}{"full_text": "b", "_x": true}}, `{"_x":true,"full_text":"a"}`},
+		{"lazy text", Block{LazyText: TextFunc(func() string { return "a" }), Extra: map[string]interface{}{"_x": nil}}, `{"_x":null,"full_text":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(&tt.block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("got %s, want %s", data, tt.json)
+			}
+		})
+	}
+
+	if _, err := json.Marshal(&Block{Extra: map[string]interface{}{"_x": make(chan int)}}); err == nil {
+		t.Error("encoding an invalid extra key succeeded")
+	}
+
+	// clones do not share nested extra values
+	b := &Block{Extra: map[string]interface{}{"_tags": []interface{}{"x"}, "_meta": map[string]interface{}{"a": 1}}}
+	c := b.Clone()
+	c.Extra["_tags"].([]interface{})[0] = "y"
+	c.Extra["_meta"].(map[string]interface{})["a"] = 2
+	if b.Extra["_tags"].([]interface{})[0] != "x" || b.Extra["_meta"].(map[string]interface{})["a"] != 1 {
+		t.Errorf("changing the extra keys of a clone changed the original: %v", b.Extra)
+	}
+}
+
+func TestStatusLineUnmarshal(t *testing.T) {
+	full := &Block{
+		Name: "cpu", Instance: "0", FullText: "<b>42%</b>", ShortText: "42",
+		Color: "#ff0000", Background: "#00000080", Border: "#ffffff",
+		BorderTop: Int(0), BorderRight: Int(1), BorderBottom: Int(2), BorderLeft: Int(3),
+		MinWidth: MinWidthText("100%"), Align: Right, Urgent: true,
+		Separator: Bool(false), SeparatorBlockWidth: 9, Markup: Pango,
+		Extra: map[string]interface{}{"_id": "x"},
+	}
+	data, err := json.Marshal(StatusLine{full, {FullText: "b", MinWidth: MinWidthPixels(20)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line StatusLine
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatal(err)
+	}
+	want := StatusLine{full, {FullText: "b", MinWidth: MinWidthPixels(20)}}
+	if !reflect.DeepEqual(line, want) {
+		t.Errorf("round trip got %+v, want %+v", line, want)
+	}
+
+	tests := []struct {
+		name string
+		json string
+		want StatusLine
+		err  bool
+	}{
+		{name: "empty", json: `[]`, want: StatusLine{}},
+		// unknown keys are kept in Extra
+		{name: "extra keys", json: `[{"full_text":"a","_n":1,"_tags":["x"]}]`, want: StatusLine{{FullText: "a", Extra: map[string]interface{}{"_n": 1.0, "_tags": []interface{}{"x"}}}}},
+		{name: "normalized values", json: `[{"full_text":"a","color":"red","align":"center","markup":"Pango"}]`, want: StatusLine{{FullText: "a", Color: "#ff0000", Align: Center, Markup: Pango}}},
+		{name: "null block", json: `[{"full_text":"a"},null]`, err: true},
+		{name: "not an array", json: `{"full_text":"a"}`, err: true},
+		{name: "invalid color", json: `[{"full_text":"a","color":"#1"}]`, err: true},
+		{name: "unknown align", json: `[{"full_text":"a","align":"justify"}]`, err: true},
+		{name: "invalid min width", json: `[{"full_text":"a","min_width":true}]`, err: true},
+		{name: "truncated", json: `[{"full_text":"a"}`, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var line StatusLine
+			err := json.Unmarshal([]byte(tt.json), &line)
+			if tt.err {
+				if err == nil {
+					t.Errorf("decoding succeeded: %+v", line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(line, tt.want) {
+				t.Errorf("got %+v, want %+v", line, tt.want)
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	orig := &Block{
+		Name: "a", FullText: "a", Color: "#ff0000",
+		BorderTop: Int(1), BorderRight: Int(1), BorderBottom: Int(1), BorderLeft: Int(1),
+		MinWidth: MinWidthPixels(10), Separator: Bool(true),
+		Extra: map[string]interface{}{"_list": []interface{}{map[string]interface{}{"a": 1}}},
+	}
+	line := StatusLine{orig, nil}
+	clone := line.Clone()
+	if !reflect.DeepEqual(clone, line) {
+		t.Fatalf("clone %+v differs from %+v", clone, line)
+	}
+
+	c := clone[0]
+	c.FullText = "b"
+	*c.BorderTop, *c.BorderRight, *c.BorderBottom, *c.BorderLeft = 0, 0, 0, 0
+	c.MinWidth.Pixels = 20
+	*c.Separator = false
+	c.Extra["_list"].([]interface{})[0].(map[string]interface{})["a"] = 2
+	c.Extra["_new"] = true
+	want := &Block{
+		Name: "a", FullText: "a", Color: "#ff0000",
+		BorderTop: Int(1), BorderRight: Int(1), BorderBottom: Int(1), BorderLeft: Int(1),
+		MinWidth: MinWidthPixels(10), Separator: Bool(true),
+		Extra: map[string]interface{}{"_list": []interface{}{map[string]interface{}{"a": 1}}},
+	}
+	if !reflect.DeepEqual(orig, want) {
+		t.Errorf("changing the clone changed the original: %+v", orig)
+	}
+	if clone[1] != nil {
+		t.Errorf("nil block cloned to %+v", clone[1])
+	}
+
+	if StatusLine(nil).Clone() != nil {
+		t.Error("clone of nil status line is not nil")
+	}
+	if (*Block)(nil).Clone() != nil {
+		t.Error("clone of nil block is not nil")
+	}
+}
@@ -0,0 +1,175 @@
+package i3bar
+
+import "github.com/pkg/errors"
+
+// BlockBuilder builds a Block using a fluent interface.
+// Values are validated as they are set and the first error
+// is reported by Build.
+type BlockBuilder struct {
+	b   Block
+	err error
+}
+
+// NewBlock starts building a Block named name.
+func NewBlock(name string) *BlockBuilder {
+	return &BlockBuilder{b: Block{Name: name}}
+}
+
+// fail records the first error which occurred while building.
+func (bb *BlockBuilder) fail(err error) {
+	if bb.err == nil {
+		bb.err = err
+	}
+}
+
+// Instance sets the instance of the block.
+func (bb *BlockBuilder) Instance(instance string) *BlockBuilder {
+	bb.b.Instance = instance
+	return bb
+}
+
+// Text sets the full text of the block.
+func (bb *BlockBuilder) Text(text string) *BlockBuilder {
+	bb.b.FullText = text
+	return bb
+}
+
+// ShortText sets the text displayed if the status line needs to be shortened.
+func (bb *BlockBuilder) ShortText(text string) *BlockBuilder {
+	bb.b.ShortText = text
+	return bb
+}
+
+// color validates c and stores it in dst.
+func (bb *BlockBuilder) color(field string, dst *Color, c Color) *BlockBuilder {
+	if err := c.Validate(); err != nil {
+		bb.fail(errors.Wrap(err, field))
+		return bb
+	}
+	*dst = c
+	return bb
+}
+
+// Color sets the text color of the block.
+func (bb *BlockBuilder) Color(c Color) *BlockBuilder {
+	return bb.color("color", &bb.b.Color, c)
+}
+
+// Background sets the background color of the block.
+func (bb *BlockBuilder) Background(c Color) *BlockBuilder {
+	return bb.color("background", &bb.b.Background, c)
+}
+
+// Border sets the border color of the block.
+func (bb *BlockBuilder) Border(c Color) *BlockBuilder {
+	return bb.color("border", &bb.b.Border, c)
+}
+
+// BorderWidth sets the border width of all edges of the block.
+func (bb *BlockBuilder) BorderWidth(top, right, bottom, left int) *BlockBuilder {
+	if top < 0 || right < 0 || bottom < 0 || left < 0 {
+		bb.fail(errors.New("border width must not be negative"))
+		return bb
+	}
+	bb.b.BorderTop = Int(top)
+	bb.b.BorderRight = Int(right)
+	bb.b.BorderBottom = Int(bottom)
+	bb.b.BorderLeft = Int(left)
+	return bb
+}
+
+// MinWidth sets the minimum width of the block in pixels.
+func (bb *BlockBuilder) MinWidth(pixels int) *BlockBuilder {
+	if pixels < 0 {
+		bb.fail(errors.New("min_width must not be negative"))
+		return bb
+	}
+	bb.b.MinWidth = MinWidthPixels(pixels)
+	return bb
+}
+
+// MinWidthText sets the minimum width of the block to the width of text.
+func (bb *BlockBuilder) MinWidthText(text string) *BlockBuilder {
+	bb.b.MinWidth = MinWidthText(text)
+	return bb
+}
+
+// MinWidthFont sets the minimum width of the block to the width of
+// sample in pixels as measured by f.
+func (bb *BlockBuilder) MinWidthFont(f *FontMetrics, sample string) *BlockBuilder {
+	bb.b.MinWidth = f.MinWidth(sample)
+	return bb
+}
+
+// Align sets the alignment of the text within the block.
+func (bb *BlockBuilder) Align(a Alignment) *BlockBuilder {
+	if _, err := a.MarshalText(); err != nil {
+		bb.fail(err)
+		return bb
+	}
+	bb.b.Align = a
+	return bb
+}
+
+// Urgent marks the block as urgent.
+func (bb *BlockBuilder) Urgent() *BlockBuilder {
+	bb.b.Urgent = true
+	return bb
+}
+
+// Separator sets if a separator line should be drawn after the block.
+func (bb *BlockBuilder) Separator(separator bool) *BlockBuilder {
+	bb.b.Separator = Bool(separator)
+	return bb
+}
+
+// SeparatorBlockWidth sets the amount of pixels to leave blank after the block.
+func (bb *BlockBuilder) SeparatorBlockWidth(pixels int) *BlockBuilder {
+	if pixels < 0 {
+		bb.fail(errors.New("separator_block_width must not be negative"))
+		return bb
+	}
+	bb.b.SeparatorBlockWidth = pixels
+	return bb
+}
+
+// Markup sets how the text of the block should be parsed.
+func (bb *BlockBuilder) Markup(m Markup) *BlockBuilder {
+	if _, err := m.MarshalText(); err != nil {
+		bb.fail(err)
+		return bb
+	}
+	bb.b.Markup = m
+	return bb
+}
+
+// Extra sets an additional key which is merged into the encoded block.
+func (bb *BlockBuilder) Extra(key string, value interface{}) *BlockBuilder {
+	if bb.b.Extra == nil {
+		bb.b.Extra = map[string]interface{}{}
+	}
+	bb.b.Extra[key] = value
+	return bb
+}
+
+// Build validates and returns the Block.
+// The builder may be reused to build further blocks.
+func (bb *BlockBuilder) Build() (*Block, error) {
+	if bb.err != nil {
+		return nil, bb.err
+	}
+	b := bb.b.Clone()
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MustBuild is like Build but panics if the Block is invalid.
+func (bb *BlockBuilder) MustBuild() *Block {
+	b, err := bb.Build()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,80 @@
+package i3bar
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBlockBuilder(t *testing.T) {
+	b, err := NewBlock("cpu").Instance("0").Text("42%").ShortText("42").
+		Color("#ff0000").Background("#000").Border("#fff").BorderWidth(0, 1, 2, 3).
+		MinWidth(30).Align(Right).Urgent().Separator(false).SeparatorBlockWidth(9).
+		Markup(Pango).Extra("_id", 1).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Block{
+		Name: "cpu", Instance: "0", FullText: "42%", ShortText: "42",
+		Color: "#ff0000", Background: "#000", Border: "#fff",
+		BorderTop: Int(0), BorderRight: Int(1), BorderBottom: Int(2), BorderLeft: Int(3),
+		MinWidth: MinWidthPixels(30), Align: Right, Urgent: true, Separator: Bool(false),
+		SeparatorBlockWidth: 9, Markup: Pango, Extra: map[string]interface{}{"_id": 1},
+	}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("got %+v, want %+v", b, want)
+	}
+
+	if got := NewBlock("a").Text("a").MinWidthText("100%").MustBuild().MinWidth; got.Text != "100%" {
+		t.Errorf("got min width %+v", got)
+	}
+	if got := NewBlock("a").Text("a").MinWidthFont(monospace, "100%").MustBuild().MinWidth; got.Pixels != 20 {
+		t.Errorf("got font min width %+v, want 20 pixels", got)
+	}
+}
+
+func TestBlockBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *BlockBuilder
+		err     string
+	}{
+		{"invalid color", NewBlock("a").Text("a").Color("nope"), "color: invalid color: nope"},
+		{"invalid background", NewBlock("a").Text("a").Background("#12"), "background: invalid color"},
+		{"invalid border", NewBlock("a").Text("a").Border("#12"), "border: invalid color"},
+		{"negative border width", NewBlock("a").Text("a").BorderWidth(1, -1, 1, 1), "border width must not be negative"},
+		{"negative min width", NewBlock("a").Text("a").MinWidth(-1), "min_width must not be negative"},
+		{"negative separator width", NewBlock("a").Text("a").SeparatorBlockWidth(-1), "separator_block_width must not be negative"},
+		{"unknown align", NewBlock("a").Text("a").Align(Alignment(42)), "unknown alignment"},
+		{"unknown markup", NewBlock("a").Text("a").Markup(Markup(42)), "unknown markup"},
+		// the first error is reported
+		{"first error", NewBlock("a").Text("a").Color("nope").MinWidth(-1), "color: invalid color"},
+		// the block is validated on Build
+		{"empty text", NewBlock("a"), "full_text: must not be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.builder.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got %+v, %v, want error %q", b, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestBlockBuilderReuse(t *testing.T) {
+	bb := NewBlock("a").Text("a").Separator(true).Extra("_n", 1)
+	first := bb.MustBuild()
+	second := bb.Text("b").Extra("_n", 2).MustBuild()
+	*second.Separator = false
+	if first.FullText != "a" || first.Extra["_n"] != 1 || !*first.Separator {
+		t.Errorf("reusing the builder changed the first block: %+v", first)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustBuild of an invalid block did not panic")
+		}
+	}()
+	NewBlock("a").MustBuild()
+}
@@ -0,0 +1,289 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Button which was used to click a block.
+type Button int
+
+const (
+	// LeftClick is the primary mouse button.
+	LeftClick Button = iota + 1
+	// MiddleClick is the middle mouse button.
+	MiddleClick
+	// RightClick is the secondary mouse button.
+	RightClick
+	// ScrollUp is sent when scrolling up on a block.
+	ScrollUp
+	// ScrollDown is sent when scrolling down on a block.
+	ScrollDown
+	// ScrollLeft is sent when scrolling left on a block.
+	ScrollLeft
+	// ScrollRight is sent when scrolling right on a block.
+	ScrollRight
+	// Back is the back side button of a mouse.
+	Back
+	// Forward is the forward side button of a mouse.
+	Forward
+)
+
+// UnmarshalText decodes a human-readable string value
+// into it's computational Button value.
+func (b *Button) UnmarshalText(t []byte) error {
+	switch strings.ToLower(string(t)) {
+	case "left":
+		*b = LeftClick
+	case "middle":
+		*b = MiddleClick
+	case "right":
+		*b = RightClick
+	case "scroll_up":
+		*b = ScrollUp
+	case "scroll_down":
+		*b = ScrollDown
+	case "scroll_left":
+		*b = ScrollLeft
+	case "scroll_right":
+		*b = ScrollRight
+	case "back":
+		*b = Back
+	case "forward":
+		*b = Forward
+	default:
+		return errors.Errorf("unknown button: %s", string(t))
+	}
+	return nil
+}
+
+// MarshalText encodes the computational Button value
+// into a human-readable string value.
+func (b Button) MarshalText() ([]byte, error) {
+	var button string
+	switch b {
+	case LeftClick:
+		button = "left"
+	case MiddleClick:
+		button = "middle"
+	case RightClick:
+		button = "right"
+	case ScrollUp:
+		button = "scroll_up"
+	case ScrollDown:
+		button = "scroll_down"
+	case ScrollLeft:
+		button = "scroll_left"
+	case ScrollRight:
+		button = "scroll_right"
+	case Back:
+		button = "back"
+	case Forward:
+		button = "forward"
+	default:
+		return nil, errors.Errorf("unknown button: %d", b)
+	}
+	return []byte(button), nil
+}
+
+// IsScroll reports whether the Button is a scroll wheel direction.
+func (b Button) IsScroll() bool {
+	switch b {
+	case ScrollUp, ScrollDown, ScrollLeft, ScrollRight:
+		return true
+	}
+	return false
+}
+
+// String returns the human-readable name of the Button.
+func (b Button) String() string {
+	t, err := b.MarshalText()
+	if err != nil {
+		return "button" + strconv.Itoa(int(b))
+	}
+	return string(t)
+}
+
+// UnmarshalJSON decodes the raw button number sent by i3bar.
+// The human-readable names accepted by UnmarshalText are supported as well.
+func (b *Button) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return b.UnmarshalText([]byte(name))
+	}
+	var n int
+	if err := json.Unmarshal(data, &n); err != nil {
+		return errors.Wrap(err, "Failed to decode button")
+	}
+	*b = Button(n)
+	return nil
+}
+
+// MarshalJSON encodes the Button as the raw number used by i3bar.
+func (b Button) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(b))
+}
+
+// Modifier key which may be held down while clicking a block.
+type Modifier uint
+
+const (
+	// Shift modifier key.
+	Shift Modifier = 1 << iota
+	// Lock modifier key. (usually Caps Lock)
+	Lock
+	// Control modifier key.
+	Control
+	// Mod1 modifier key. (usually Alt)
+	Mod1
+	// Mod2 modifier key. (usually Num Lock)
+	Mod2
+	// Mod3 modifier key.
+	Mod3
+	// Mod4 modifier key. (usually Super)
+	Mod4
+	// Mod5 modifier key.
+	Mod5
+)
+
+// modifierNames in the order of their bits.
+var modifierNames = []string{"Shift", "Lock", "Control", "Mod1", "Mod2", "Mod3", "Mod4", "Mod5"}
+
+// UnmarshalText decodes the modifier name sent by i3bar
+// into it's computational Modifier value.
+func (m *Modifier) UnmarshalText(b []byte) error {
+	for i, name := range modifierNames {
+		if strings.EqualFold(name, string(b)) {
+			*m = 1 << uint(i)
+			return nil
+		}
+	}
+	return errors.Errorf("unknown modifier: %s", string(b))
+}
+
+// MarshalText encodes the computational Modifier value
+// into the modifier name used by i3bar.
+func (m Modifier) MarshalText() ([]byte, error) {
+	for i, name := range modifierNames {
+		if m == 1<<uint(i) {
+			return []byte(name), nil
+		}
+	}
+	return nil, errors.Errorf("unknown modifier: %d", m)
+}
+
+// Modifiers is a bitmask of all Modifier keys held down while clicking a block.
+type Modifiers Modifier
+
+// Has returns true if all of the specified modifiers are set.
+func (ms Modifiers) Has(m Modifier) bool {
+	return Modifier(ms)&m == m
+}
+
+// List returns all set modifiers in the order of their bits.
+func (ms Modifiers) List() []Modifier {
+	var list []Modifier
+	for i := range modifierNames {
+		if m := Modifier(1 << uint(i)); ms.Has(m) {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+// UnmarshalJSON decodes the modifiers array sent by i3bar into a bitmask.
+// Unknown modifiers are ignored.
+func (ms *Modifiers) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return errors.Wrap(err, "Failed to decode modifiers")
+	}
+	*ms = 0
+	for _, name := range names {
+		var m Modifier
+		if err := m.UnmarshalText([]byte(name)); err != nil {
+			continue
+		}
+		*ms |= Modifiers(m)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the bitmask into the modifiers array used by i3bar.
+func (ms Modifiers) MarshalJSON() ([]byte, error) {
+	names := []string{}
+	for i, name := range modifierNames {
+		if ms.Has(1 << uint(i)) {
+			names = append(names, name)
+		}
+	}
+	return json.Marshal(names)
+}
+
+// ClickEvent is sent by i3bar when the user clicks on a block.
+type ClickEvent struct {
+	// Name of the clicked block.
+	Name string `json:"name,omitempty"`
+
+	// Instance of the clicked block.
+	Instance string `json:"instance,omitempty"`
+
+	// Button which was used to click the block.
+	Button Button `json:"button"`
+
+	// Modifiers which were held down while clicking the block.
+	Modifiers Modifiers `json:"modifiers,omitempty"`
+
+	// X coordinate of the click relative to the top left corner of the root window.
+	X int `json:"x"`
+
+	// Y coordinate of the click relative to the top left corner of the root window.
+	Y int `json:"y"`
+
+	// RelativeX coordinate of the click relative to the top left corner of the block.
+	RelativeX int `json:"relative_x"`
+
+	// RelativeY coordinate of the click relative to the top left corner of the block.
+	RelativeY int `json:"relative_y"`
+
+	// OutputX coordinate of the click relative to the top left corner of the output.
+	OutputX int `json:"output_x"`
+
+	// OutputY coordinate of the click relative to the top left corner of the output.
+	OutputY int `json:"output_y"`
+
+	// Width of the clicked block in pixels.
+	Width int `json:"width"`
+
+	// Height of the clicked block in pixels.
+	Height int `json:"height"`
+
+	// Delta is the number of consecutive scroll events coalesced into
+	// this event, see WithScrollDebounce. It is 0 for events which
+	// have not been coalesced.
+	Delta int `json:"-"`
+
+	// Double is set if this event is the second of two clicks with the
+	// same button on the same block, see WithDoubleClick.
+	Double bool `json:"-"`
+}
+
+// Fraction returns the position of the click within the clicked block
+// from 0.0 at the left to 1.0 at the right border.
+// ok is false if the event does not contain the block width.
+func (ev ClickEvent) Fraction() (f float64, ok bool) {
+	if ev.Width <= 0 {
+		return 0, false
+	}
+	f = float64(ev.RelativeX) / float64(ev.Width)
+	switch {
+	case f < 0:
+		f = 0
+	case f > 1:
+		f = 1
+	}
+	return f, true
+}
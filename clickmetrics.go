@@ -0,0 +1,102 @@
+package i3bar
+
+import (
+	"time"
+)
+
+// ClickOutcome is a stage of handling a click event.
+type ClickOutcome int
+
+const (
+	// ClickReceived is reported for every click event read from i3bar.
+	ClickReceived ClickOutcome = iota
+	// ClickDispatched is reported once a click event reached a
+	// click handler or the Events channel.
+	ClickDispatched
+	// ClickDropped is reported if a click event got filtered by a
+	// click middleware, expired from the buffer of WithClickReplay or
+	// the stream stopped before it was delivered.
+	ClickDropped
+	// ClickHandled is reported once a click handler returned.
+	ClickHandled
+)
+
+// ClickMetric describes a single stage of handling a click event.
+type ClickMetric struct {
+	// Name of the clicked block.
+	Name string
+
+	// Instance of the clicked block.
+	Instance string
+
+	// Outcome is the reached stage.
+	Outcome ClickOutcome
+
+	// Duration the click handler took for ClickHandled.
+	Duration time.Duration
+}
+
+// ClickStats are the counters of the click events of a block.
+type ClickStats struct {
+	Received   uint64
+	Dispatched uint64
+	Dropped    uint64
+	Handled    uint64
+
+	// HandlerTime is the total time taken by click handlers.
+	HandlerTime time.Duration
+}
+
+// OnClickMetric registers fn to be called for every stage of handling
+// a click event, e.g. to export metrics. fn must not block.
+//
+// This function is thread safe.
+func (s *Stream) OnClickMetric(fn func(m ClickMetric)) {
+	s.statsMux.Lock()
+	defer s.statsMux.Unlock()
+	s.onClickMetric = fn
+}
+
+// ClickStats returns the counters of the click events
+// of the block with the given name and instance.
+//
+// This function is thread safe.
+func (s *Stream) ClickStats(name, instance string) ClickStats {
+	s.statsMux.Lock()
+	defer s.statsMux.Unlock()
+	if stats := s.clickStats[clickRoute{name: name, instance: instance}]; stats != nil {
+		return *stats
+	}
+	return ClickStats{}
+}
+
+// countClick records a stage of handling a click event on the block
+// identified by key and reports it to the registered callback.
+func (s *Stream) countClick(key clickRoute, outcome ClickOutcome, d time.Duration) {
+	s.statsMux.Lock()
+	if s.clickStats == nil {
+		s.clickStats = map[clickRoute]*ClickStats{}
+	}
+	stats := s.clickStats[key]
+	if stats == nil {
+		stats = &ClickStats{}
+		s.clickStats[key] = stats
+	}
+	switch outcome {
+	case ClickReceived:
+		stats.Received++
+	case ClickDispatched:
+		stats.Dispatched++
+	case ClickDropped:
+		stats.Dropped++
+	case ClickHandled:
+		stats.Handled++
+		stats.HandlerTime += d
+	}
+	fn := s.onClickMetric
+	s.statsMux.Unlock()
+
+	if fn != nil {
+		fn(ClickMetric{Name: key.name, Instance: key.instance, Outcome: outcome, Duration: d})
+	}
+}
@@ -0,0 +1,93 @@
+package i3bar
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClickMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(s *Stream)
+		outcomes []ClickOutcome
+		want     ClickStats
+	}{
+		{
+			name: "handled",
+			setup: func(s *Stream) {
+				s.OnClick("a", "", func(ClickEvent) { time.Sleep(time.Millisecond) })
+			},
+			outcomes: []ClickOutcome{ClickReceived, ClickDispatched, ClickHandled},
+			want:     ClickStats{Received: 1, Dispatched: 1, Handled: 1},
+		},
+		{
+			name: "dropped by middleware",
+			setup: func(s *Stream) {
+				s.UseClick(func(ClickEvent, func(ClickEvent)) {})
+			},
+			outcomes: []ClickOutcome{ClickReceived, ClickDropped},
+			want:     ClickStats{Received: 1, Dropped: 1},
+		},
+		{
+			name: "events channel",
+			setup: func(s *Stream) {
+				go func() { <-s.Events() }()
+			},
+			outcomes: []ClickOutcome{ClickReceived, ClickDispatched},
+			want:     ClickStats{Received: 1, Dispatched: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := io.Pipe()
+			defer w.Close()
+			s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), WithClickEvents())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			var mux sync.Mutex
+			var outcomes []ClickOutcome
+			var handlerTime time.Duration
+			s.OnClickMetric(func(m ClickMetric) {
+				mux.Lock()
+				defer mux.Unlock()
+				if m.Name != "a" || m.Instance != "0" {
+					t.Errorf("got metric of %s/%s, want a/0", m.Name, m.Instance)
+				}
+				outcomes = append(outcomes, m.Outcome)
+				handlerTime += m.Duration
+			})
+			tt.setup(s)
+
+			io.WriteString(w, "[\n"+`{"name":"a","instance":"0","button":1}`+"\n")
+			waitFor(t, "click metrics", func() bool {
+				mux.Lock()
+				defer mux.Unlock()
+				return len(outcomes) >= len(tt.outcomes)
+			})
+
+			mux.Lock()
+			defer mux.Unlock()
+			if !reflect.DeepEqual(outcomes, tt.outcomes) {
+				t.Errorf("got outcomes %v, want %v", outcomes, tt.outcomes)
+			}
+			got := s.ClickStats("a", "0")
+			if got.HandlerTime != handlerTime || (tt.want.Handled > 0) != (got.HandlerTime > 0) {
+				t.Errorf("got handler time %v, reported %v", got.HandlerTime, handlerTime)
+			}
+			got.HandlerTime = 0
+			if got != tt.want {
+				t.Errorf("got stats %+v, want %+v", got, tt.want)
+			}
+			if other := s.ClickStats("a", ""); other != (ClickStats{}) {
+				t.Errorf("counted for other instance: %+v", other)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package i3bar
+
+import (
+	"time"
+)
+
+// clickQueue holds the click handler calls of a single block
+// which are waiting to be run in order.
+type clickQueue struct {
+	calls   []func()
+	running bool
+}
+
+// runClick runs the handler call fn for a click event on the block
+// identified by key. With WithAsyncClicks the call is queued and run
+// on a goroutine once all previous calls for the block are finished,
+// otherwise it is run right away.
+func (s *Stream) runClick(key clickRoute, fn func()) {
+	call := fn
+	fn = func() {
+		start := time.Now()
+		call()
+		s.countClick(key, ClickHandled, time.Since(start))
+	}
+	if !s.asyncClicks {
+		fn()
+		return
+	}
+
+	s.qMux.Lock()
+	defer s.qMux.Unlock()
+	if s.queues == nil {
+		s.queues = map[clickRoute]*clickQueue{}
+	}
+	q := s.queues[key]
+	if q == nil {
+		q = &clickQueue{}
+		s.queues[key] = q
+	}
+	q.calls = append(q.calls, fn)
+	if !q.running {
+		q.running = true
+		go s.drainClicks(key, q)
+	}
+}
+
+// drainClicks runs the calls of q one after another until it is empty.
+func (s *Stream) drainClicks(key clickRoute, q *clickQueue) {
+	for {
+		s.qMux.Lock()
+		if len(q.calls) == 0 {
+			delete(s.queues, key)
+			s.qMux.Unlock()
+			return
+		}
+		fn := q.calls[0]
+		q.calls = q.calls[1:]
+		s.qMux.Unlock()
+
+		if s.clickSlots != nil {
+			s.clickSlots <- struct{}{}
+		}
+		fn()
+		if s.clickSlots != nil {
+			<-s.clickSlots
+		}
+	}
+}
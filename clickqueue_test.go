@@ -0,0 +1,129 @@
+package i3bar
+
+import (
+	"bytes"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAsyncClicksLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		limit      int
+		concurrent bool
+	}{
+		{"unlimited", 0, true},
+		{"limit 2", 2, true},
+		{"limit 1", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithAsyncClicks(tt.limit))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			started, release := make(chan struct{}), make(chan struct{})
+			done := make(chan string, 2)
+			s.runClick(clickRoute{name: "a"}, func() {
+				close(started)
+				<-release
+				done <- "a"
+			})
+			<-started
+			s.runClick(clickRoute{name: "b"}, func() { done <- "b" })
+
+			if tt.concurrent {
+				select {
+				case got := <-done:
+					if got != "b" {
+						t.Errorf("got %q, want b", got)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("slow handler of another block delayed the click")
+				}
+				close(release)
+				<-done
+				return
+			}
+			select {
+			case got := <-done:
+				t.Fatalf("%s handled beyond the limit", got)
+			case <-time.After(20 * time.Millisecond):
+			}
+			close(release)
+			for _, want := range []string{"a", "b"} {
+				if got := <-done; got != want {
+					t.Errorf("got %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAsyncClicksOrder(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithAsyncClicks(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var running int32
+	release := make(chan struct{})
+	got := make(chan int, 3)
+	key := clickRoute{name: "a"}
+	for i := 1; i <= 3; i++ {
+		i := i
+		s.runClick(key, func() {
+			if atomic.AddInt32(&running, 1) != 1 {
+				t.Error("click handlers of the same block run concurrently")
+			}
+			if i == 1 {
+				<-release
+			}
+			atomic.AddInt32(&running, -1)
+			got <- i
+		})
+	}
+	close(release)
+
+	var order []int
+	for len(order) < 3 {
+		select {
+		case i := <-got:
+			order = append(order, i)
+		case <-time.After(time.Second):
+			t.Fatalf("handled %v, handlers missing", order)
+		}
+	}
+	if !reflect.DeepEqual(order, []int{1, 2, 3}) {
+		t.Errorf("handled in order %v, want 1 2 3", order)
+	}
+	waitFor(t, "handled metrics", func() bool { return s.ClickStats("a", "").Handled == 3 })
+	waitFor(t, "drained queue", func() bool {
+		s.qMux.Lock()
+		defer s.qMux.Unlock()
+		return len(s.queues) == 0
+	})
+}
+
+func TestSyncClicks(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// without WithAsyncClicks handlers run on the calling goroutine
+	handled := false
+	s.runClick(clickRoute{name: "a"}, func() { handled = true })
+	if !handled {
+		t.Error("handler did not run before runClick returned")
+	}
+	if stats := s.ClickStats("a", ""); stats.Handled != 1 {
+		t.Errorf("got %+v, want one handled click", stats)
+	}
+}
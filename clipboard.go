@@ -0,0 +1,70 @@
+package i3bar
+
+import (
+	"html"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// clipboardCommands are tried in order to copy text to the clipboard.
+var clipboardCommands = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+// CopyToClipboard copies text to the clipboard using the first available
+// of wl-copy (on Wayland), xclip and xsel.
+func CopyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		if args[0] == "wl-copy" && os.Getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return errors.Wrapf(err, "Failed to copy to clipboard with %s", args[0])
+		}
+		return nil
+	}
+	return errors.New("no clipboard command found")
+}
+
+// CopyText returns a click handler which copies text to the clipboard
+// on left clicks. Errors are reported on s.Errors().
+func (s *Stream) CopyText(text string) func(ev ClickEvent) {
+	return s.copyOnClick(func() string { return text })
+}
+
+// CopyOnClick sets the OnClick handler of b to copy the text of b
+// to the clipboard on left clicks, e.g. for blocks showing an IP address
+// or a commit hash. Pango markup is removed from the copied text.
+// Errors are reported on s.Errors(). b is returned for convenience.
+func (s *Stream) CopyOnClick(b *Block) *Block {
+	b.OnClick = s.copyOnClick(func() string {
+		text := b.Text()
+		if b.Markup == Pango {
+			text = html.UnescapeString(anyTag.ReplaceAllString(text, ""))
+		}
+		return text
+	})
+	return b
+}
+
+// copyOnClick returns a click handler copying the result of text.
+func (s *Stream) copyOnClick(text func() string) func(ev ClickEvent) {
+	return func(ev ClickEvent) {
+		if ev.Button != LeftClick {
+			return
+		}
+		if err := CopyToClipboard(text()); err != nil {
+			s.reportError(err)
+		}
+	}
+}
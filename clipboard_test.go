@@ -0,0 +1,117 @@
+package i3bar
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeClipboard installs scripts named like the clipboard commands
+// in commands into an empty PATH, which record the copied text and the
+// command used to the returned file. Failing commands exit with 1.
+func fakeClipboard(t *testing.T, wayland bool, commands map[string]bool) string {
+	t.Helper()
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not found")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "copied")
+	for name, fail := range commands {
+		script := "#!/bin/sh\necho " + name + " > \"" + out + "\"\n" + cat + " >> \"" + out + "\"\n"
+		if fail {
+			script = "#!/bin/sh\nexit 1\n"
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	display := ""
+	if wayland {
+		display = "wayland-0"
+	}
+	t.Setenv("WAYLAND_DISPLAY", display)
+	return out
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	tests := []struct {
+		name     string
+		wayland  bool
+		commands map[string]bool
+		want     string
+		err      string
+	}{
+		{"wayland", true, map[string]bool{"wl-copy": false, "xclip": false}, "wl-copy", ""},
+		{"x11", false, map[string]bool{"wl-copy": false, "xclip": false}, "xclip", ""},
+		{"xsel", false, map[string]bool{"xsel": false}, "xsel", ""},
+		{"no command", true, nil, "", "no clipboard command found"},
+		{"failing command", false, map[string]bool{"xclip": true, "xsel": false}, "", "with xclip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := fakeClipboard(t, tt.wayland, tt.commands)
+			err := CopyToClipboard("text")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			copied, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(copied), tt.want+"\ntext"; got != want {
+				t.Errorf("copied %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCopyOnClick(t *testing.T) {
+	out := fakeClipboard(t, false, map[string]bool{"xclip": false})
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		name    string
+		handler func(ClickEvent)
+		button  Button
+		want    string
+	}{
+		{"text", s.CopyText("abc"), LeftClick, "abc"},
+		{"block", s.CopyOnClick(&Block{FullText: "10.0.0.1"}).OnClick, LeftClick, "10.0.0.1"},
+		{"pango block", s.CopyOnClick(&Block{FullText: "<b>a</b> &amp; b", Markup: Pango}).OnClick, LeftClick, "a & b"},
+		{"other buttons", s.CopyText("abc"), RightClick, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(out)
+			tt.handler(ClickEvent{Button: tt.button})
+			copied, err := os.ReadFile(out)
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("copied %q", copied)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(copied), "xclip\n"+tt.want; got != want {
+				t.Errorf("copied %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package i3bar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Color in hex notation. (#rgb, #rrggbb, #rgba or #rrggbbaa)
+// Color names like "steelblue" or "gray20" are supported as well
+// and are sent to i3bar in hex notation.
+// An empty Color is not sent to i3bar, so that i3bar's default is used.
+//
+// Only swaybar supports colors with alpha channel.
+// See WithTarget on how alpha channels are handled by a Stream.
+type Color string
+
+// RGB creates an opaque Color from its red, green and blue components.
+func RGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+}
+
+// RGBA creates a Color from its red, green, blue and alpha components.
+func RGBA(r, g, b, a uint8) Color {
+	return Color(fmt.Sprintf("#%02x%02x%02x%02x", r, g, b, a))
+}
+
+// ParseColor parses a Color in hex notation (#rgb, #rrggbb, #rgba or #rrggbbaa)
+// or a color name and returns it normalized to #rrggbb or #rrggbbaa.
+func ParseColor(s string) (Color, error) {
+	r, g, b, a, alpha, err := parseHex(s)
+	if err != nil {
+		return "", err
+	}
+	if !alpha {
+		return RGB(r, g, b), nil
+	}
+	return RGBA(r, g, b, a), nil
+}
+
+// MustParseColor is like ParseColor but panics if s is not a valid color.
+func MustParseColor(s string) Color {
+	c, err := ParseColor(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NamedColor returns the Color for a CSS color name like "steelblue"
+// or an X11 gray level like "gray20".
+func NamedColor(name string) (Color, error) {
+	c, ok := namedColor(name)
+	if !ok {
+		return "", errors.Errorf("unknown color name: %s", name)
+	}
+	return c, nil
+}
+
+// parseHex parses a color in hex notation or a color name. alpha reports
+// whether the color explicitly specifies an alpha channel.
+func parseHex(s string) (r, g, b, a uint8, alpha bool, err error) {
+	invalid := errors.Errorf("invalid color: %s", s)
+	if !strings.HasPrefix(s, "#") {
+		if c, ok := namedColor(s); ok {
+			return parseHex(string(c))
+		}
+		return 0, 0, 0, 0, false, invalid
+	}
+	hex := s[1:]
+
+	switch len(hex) {
+	case 3, 4:
+		// expand #rgb(a) to #rrggbb(aa)
+		long := make([]byte, 0, 2*len(hex))
+		for i := 0; i < len(hex); i++ {
+			long = append(long, hex[i], hex[i])
+		}
+		hex = string(long)
+	case 6, 8:
+	default:
+		return 0, 0, 0, 0, false, invalid
+	}
+
+	alpha = len(hex) == 8
+	if !alpha {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, 0, 0, 0, false, invalid
+	}
+	return uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v), alpha, nil
+}
+
+// RGB returns the red, green and blue components of the Color.
+func (c Color) RGB() (r, g, b uint8, err error) {
+	r, g, b, _, err = c.RGBA()
+	return r, g, b, err
+}
+
+// RGBA returns the red, green, blue and alpha components of the Color.
+// The alpha component of colors without alpha channel is 0xff.
+func (c Color) RGBA() (r, g, b, a uint8, err error) {
+	r, g, b, a, _, err = parseHex(string(c))
+	return r, g, b, a, err
+}
+
+// HasAlpha returns true if the Color specifies an alpha channel.
+func (c Color) HasAlpha() bool {
+	_, _, _, _, alpha, err := parseHex(string(c))
+	return err == nil && alpha
+}
+
+// WithoutAlpha returns the Color with its alpha channel stripped.
+// Invalid colors are returned unchanged.
+func (c Color) WithoutAlpha() Color {
+	r, g, b, _, alpha, err := parseHex(string(c))
+	if err != nil || !alpha {
+		return c
+	}
+	return RGB(r, g, b)
+}
+
+// Validate checks if the Color is either empty or a valid color.
+func (c Color) Validate() error {
+	if c == "" {
+		return nil
+	}
+	_, _, _, _, _, err := parseHex(string(c))
+	return err
+}
+
+// String returns the Color in hex notation.
+func (c Color) String() string {
+	return string(c)
+}
+
+// UnmarshalText decodes and validates a Color in hex notation or a color name.
+func (c *Color) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*c = ""
+		return nil
+	}
+	color, err := ParseColor(string(b))
+	if err != nil {
+		return err
+	}
+	*c = color
+	return nil
+}
+
+// MarshalText validates the Color and encodes it as #rrggbb or #rrggbbaa.
+func (c Color) MarshalText() ([]byte, error) {
+	if c == "" {
+		return []byte{}, nil
+	}
+	color, err := ParseColor(string(c))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(color), nil
+}
+
+// stripAlpha returns line with the alpha channel stripped from all block colors.
+// Blocks with alpha channels are copied, so that line itself is never modified.
+func stripAlpha(line StatusLine) StatusLine {
+	out := make(StatusLine, len(line))
+	for i, b := range line {
+		if b == nil || !(b.Color.HasAlpha() || b.Background.HasAlpha() || b.Border.HasAlpha()) {
+			out[i] = b
+			continue
+		}
+		stripped := *b
+		stripped.Color = b.Color.WithoutAlpha()
+		stripped.Background = b.Background.WithoutAlpha()
+		stripped.Border = b.Border.WithoutAlpha()
+		out[i] = &stripped
+	}
+	return out
+}
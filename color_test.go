@@ -0,0 +1,136 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  Color
+		alpha bool
+		err   bool
+	}{
+		{in: "#ff0000", want: "#ff0000"},
+		{in: "#FF8000", want: "#ff8000"},
+		{in: "#f80", want: "#ff8800"},
+		{in: "#f808", want: "#ff880088", alpha: true},
+		{in: "#ff00", want: "#ffff0000", alpha: true},
+		{in: "#ff000080", want: "#ff000080", alpha: true},
+		{in: "#ff0000ff", want: "#ff0000ff", alpha: true},
+		{in: "steelblue", want: "#4682b4"},
+		{in: "Steel Blue", want: "#4682b4"},
+		{in: "gray", want: "#808080"},
+		{in: "grey20", want: "#333333"},
+		{in: "gray0", want: "#000000"},
+		{in: "gray100", want: "#ffffff"},
+		{in: "gray101", err: true},
+		{in: "", err: true},
+		{in: "#", err: true},
+		{in: "#ff000", err: true},
+		{in: "#ff00000", err: true},
+		{in: "#gg0000", err: true},
+		{in: "ff0000", err: true},
+		{in: "notacolor", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ParseColor(%q) = %q, want error", tt.in, got)
+			}
+			if Color(tt.in).Validate() == nil && tt.in != "" {
+				t.Errorf("Color(%q).Validate() succeeded", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseColor(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got.HasAlpha() != tt.alpha {
+			t.Errorf("%q.HasAlpha() = %v, want %v", got, got.HasAlpha(), tt.alpha)
+		}
+	}
+}
+
+func TestColorComponents(t *testing.T) {
+	r, g, b, a, err := Color("#12345678").RGBA()
+	if err != nil || r != 0x12 || g != 0x34 || b != 0x56 || a != 0x78 {
+		t.Errorf("RGBA() = %x %x %x %x %v", r, g, b, a, err)
+	}
+	if _, _, _, a, _ := Color("#123456").RGBA(); a != 0xff {
+		t.Errorf("alpha of opaque color = %x, want ff", a)
+	}
+	if got := Color("#12345678").WithoutAlpha(); got != "#123456" {
+		t.Errorf("WithoutAlpha() = %q", got)
+	}
+	if got := Color("invalid").WithoutAlpha(); got != "invalid" {
+		t.Errorf("WithoutAlpha() of invalid color = %q", got)
+	}
+	if got := RGB(1, 2, 255); got != "#0102ff" {
+		t.Errorf("RGB() = %q", got)
+	}
+}
+
+func TestColorJSON(t *testing.T) {
+	data, err := json.Marshal(&Block{FullText: "a", Color: "red", Background: "#0f0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"full_text":"a","color":"#ff0000","background":"#00ff00"}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+	if _, err := json.Marshal(&Block{Color: "invalid"}); err == nil {
+		t.Error("encoding an invalid color succeeded")
+	}
+
+	var b Block
+	if err := json.Unmarshal([]byte(`{"full_text":"a","color":"White","border":""}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Color != "#ffffff" || b.Border != "" {
+		t.Errorf("decoded colors %q and %q", b.Color, b.Border)
+	}
+	if err := json.Unmarshal([]byte(`{"color":"#12"}`), &b); err == nil {
+		t.Error("decoding an invalid color succeeded")
+	}
+}
+
+func TestAlphaTarget(t *testing.T) {
+	line := StatusLine{{FullText: "a", Color: "#ff000080", Background: "#00ff00", Border: "#0000ffcc"}}
+	tests := []struct {
+		name   string
+		target Target
+		want   string
+	}{
+		// i3bar does not support alpha channels
+		{"i3bar", I3bar, `[{"full_text":"a","color":"#ff0000","background":"#00ff00","border":"#0000ff"}]`},
+		{"swaybar", Swaybar, `[{"full_text":"a","color":"#ff000080","background":"#00ff00","border":"#0000ffcc"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, false, DefaultHeader(), WithTarget(tt.target))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SendLine(line); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+			if got := statusLines(out.String())[0]; got != "["+tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+	// the status line of the caller is never modified
+	if line[0].Color != "#ff000080" {
+		t.Errorf("caller's block modified: %q", line[0].Color)
+	}
+}
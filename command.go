@@ -0,0 +1,70 @@
+package i3bar
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClickCommand returns a click handler which runs command with sh -c
+// for every click event. The click event is exported to the command
+// in the environment variables used by i3blocks:
+// BLOCK_NAME, BLOCK_INSTANCE, BLOCK_BUTTON, BLOCK_X and BLOCK_Y
+// as well as name, instance, button, modifiers, x, y, relative_x,
+// relative_y, output_x, output_y, width and height.
+//
+// The command runs in the background and its output is discarded.
+// Errors are reported on s.Errors().
+func (s *Stream) ClickCommand(command string) func(ev ClickEvent) {
+	return func(ev ClickEvent) {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(), ClickEnv(ev)...)
+		if err := cmd.Start(); err != nil {
+			s.reportError(errors.Wrapf(err, "Failed to start click command %s", command))
+			return
+		}
+		go func() {
+			if err := cmd.Wait(); err != nil {
+				s.reportError(errors.Wrapf(err, "Click command %s failed", command))
+			}
+		}()
+	}
+}
+
+// ClickEnv returns the i3blocks compatible environment
+// variables describing ev in the form "key=value".
+func ClickEnv(ev ClickEvent) []string {
+	var modifiers []string
+	for _, m := range ev.Modifiers.List() {
+		if name, err := m.MarshalText(); err == nil {
+			modifiers = append(modifiers, string(name))
+		}
+	}
+
+	button := strconv.Itoa(int(ev.Button))
+	x, y := strconv.Itoa(ev.X), strconv.Itoa(ev.Y)
+	return []string{
+		// legacy i3blocks variables
+		"BLOCK_NAME=" + ev.Name,
+		"BLOCK_INSTANCE=" + ev.Instance,
+		"BLOCK_BUTTON=" + button,
+		"BLOCK_X=" + x,
+		"BLOCK_Y=" + y,
+
+		"name=" + ev.Name,
+		"instance=" + ev.Instance,
+		"button=" + button,
+		"modifiers=" + strings.Join(modifiers, ","),
+		"x=" + x,
+		"y=" + y,
+		"relative_x=" + strconv.Itoa(ev.RelativeX),
+		"relative_y=" + strconv.Itoa(ev.RelativeY),
+		"output_x=" + strconv.Itoa(ev.OutputX),
+		"output_y=" + strconv.Itoa(ev.OutputY),
+		"width=" + strconv.Itoa(ev.Width),
+		"height=" + strconv.Itoa(ev.Height),
+	}
+}
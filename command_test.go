@@ -0,0 +1,76 @@
+package i3bar
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClickEnv(t *testing.T) {
+	ev := ClickEvent{
+		Name: "vol", Instance: "master", Button: ScrollUp,
+		X: 1800, Y: 10, RelativeX: 12, RelativeY: 8, OutputX: 800, OutputY: 10, Width: 60, Height: 20,
+	}
+
+	tests := []struct {
+		name      string
+		modifiers Modifiers
+		want      []string
+	}{
+		{"no modifiers", 0, []string{
+			"BLOCK_NAME=vol", "BLOCK_INSTANCE=master", "BLOCK_BUTTON=4", "BLOCK_X=1800", "BLOCK_Y=10",
+			"name=vol", "instance=master", "button=4", "modifiers=", "x=1800", "y=10",
+			"relative_x=12", "relative_y=8", "output_x=800", "output_y=10", "width=60", "height=20",
+		}},
+		{"modifiers", Modifiers(Shift | Mod4), []string{"modifiers=Shift,Mod4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev := ev
+			ev.Modifiers = tt.modifiers
+			env := ClickEnv(ev)
+			vars := map[string]bool{}
+			for _, v := range env {
+				vars[v] = true
+			}
+			for _, want := range tt.want {
+				if !vars[want] {
+					t.Errorf("missing %s in %q", want, env)
+				}
+			}
+		})
+	}
+}
+
+func TestClickCommand(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	path := filepath.Join(t.TempDir(), "out")
+	s.ClickCommand(`echo "$BLOCK_NAME $button" > "` + path + `"`)(ClickEvent{Name: "cpu", Button: LeftClick})
+	var out []byte
+	waitFor(t, "click command", func() bool {
+		out, _ = os.ReadFile(path)
+		return strings.HasSuffix(string(out), "\n")
+	})
+	if got := string(out); got != "cpu 1\n" {
+		t.Errorf("got %q, want %q", got, "cpu 1\n")
+	}
+
+	// failing commands are reported
+	s.ClickCommand("exit 3")(ClickEvent{Name: "cpu"})
+	select {
+	case err := <-s.Errors():
+		if !strings.Contains(err.Error(), "exit 3") {
+			t.Errorf("got %v, want failure of exit 3", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("failed click command not reported")
+	}
+}
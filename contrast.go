@@ -0,0 +1,79 @@
+package i3bar
+
+import "math"
+
+// MinContrast is the contrast ratio required by WCAG AA for normal text.
+const MinContrast = 4.5
+
+// Luminance returns the relative luminance of the Color as defined by WCAG
+// from 0 for black to 1 for white. The alpha channel is ignored.
+func (c Color) Luminance() (float64, error) {
+	r, g, b, err := c.RGB()
+	if err != nil {
+		return 0, err
+	}
+	linear := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b), nil
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two colors
+// from 1 for equal colors to 21 for black on white.
+func ContrastRatio(a, b Color) (float64, error) {
+	la, err := a.Luminance()
+	if err != nil {
+		return 0, err
+	}
+	lb, err := b.Luminance()
+	if err != nil {
+		return 0, err
+	}
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05), nil
+}
+
+// ContrastOn returns a foreground Color readable on background.
+// The Color itself is returned if its contrast ratio is at least MinContrast,
+// otherwise the candidate with the highest contrast ratio. Black and white
+// are the candidates if none are given. The Color is returned unchanged
+// if background is empty or invalid.
+func (c Color) ContrastOn(background Color, candidates ...Color) Color {
+	if _, err := background.Luminance(); err != nil {
+		return c
+	}
+	if ratio, err := ContrastRatio(c, background); err == nil && ratio >= MinContrast {
+		return c
+	}
+	if len(candidates) == 0 {
+		candidates = []Color{"#000000", "#ffffff"}
+	}
+	best, bestRatio := c, 0.0
+	for _, candidate := range candidates {
+		ratio, err := ContrastRatio(candidate, background)
+		if err == nil && ratio > bestRatio {
+			best, bestRatio = candidate, ratio
+		}
+	}
+	return best
+}
+
+// AutoContrast returns a Middleware replacing the text color of all blocks
+// with a background which are not readable on it, see Color.ContrastOn.
+// Use it after middlewares setting colors, like a Theme middleware.
+func AutoContrast(candidates ...Color) Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			if b != nil && b.Background != "" {
+				b.Color = b.Color.ContrastOn(b.Background, candidates...)
+			}
+		}
+		return line
+	}
+}
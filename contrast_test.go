@@ -0,0 +1,77 @@
+package i3bar
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b Color
+		want float64
+		err  bool
+	}{
+		{a: "#000000", b: "#ffffff", want: 21},
+		{a: "#ffffff", b: "#000000", want: 21},
+		{a: "#808080", b: "#808080", want: 1},
+		{a: "#777777", b: "#ffffff", want: 4.48},
+		{a: "#ff0000", b: "#ffffff", want: 4},
+		// the alpha channel is ignored
+		{a: "#00000080", b: "#ffffff", want: 21},
+		{a: "invalid", b: "#ffffff", err: true},
+		{a: "#ffffff", b: "", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ContrastRatio(tt.a, tt.b)
+		if tt.err {
+			if err == nil {
+				t.Errorf("ContrastRatio(%q, %q) = %v, want error", tt.a, tt.b, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ContrastRatio(%q, %q): %v", tt.a, tt.b, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%q, %q) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestContrastOn(t *testing.T) {
+	tests := []struct {
+		name       string
+		color      Color
+		background Color
+		candidates []Color
+		want       Color
+	}{
+		{"readable", "#ffffff", "#000080", nil, "#ffffff"},
+		{"dark background", "#333333", "#000000", nil, "#ffffff"},
+		{"light background", "#eeeeee", "#ffff00", nil, "#000000"},
+		{"unset color", "", "#000000", nil, "#ffffff"},
+		{"candidates", "#333333", "#000000", []Color{"#ff0000", "#00ff00"}, "#00ff00"},
+		{"invalid candidates", "#333333", "#000000", []Color{"invalid"}, "#333333"},
+		{"no background", "#333333", "", nil, "#333333"},
+		{"invalid background", "#333333", "invalid", nil, "#333333"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.color.ContrastOn(tt.background, tt.candidates...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoContrast(t *testing.T) {
+	line := AutoContrast()(StatusLine{
+		{Color: "#333333", Background: "#000000"},
+		{Color: "#333333"},
+		nil,
+	})
+	if line[0].Color != "#ffffff" || line[1].Color != "#333333" {
+		t.Errorf("got colors %q and %q, want #ffffff and #333333", line[0].Color, line[1].Color)
+	}
+}
@@ -0,0 +1,120 @@
+package i3bar
+
+import (
+	"sync"
+)
+
+// Cycle is a block cycling through a list of options, e.g. to switch
+// profiles or audio sinks. A left click or scrolling down selects the
+// next option, a right click or scrolling up the previous one.
+type Cycle struct {
+	// Name of the cycle block.
+	Name string
+
+	// Instance of the cycle block.
+	Instance string
+
+	// Label is shown in front of the selected option.
+	Label string
+
+	mux      sync.Mutex
+	options  []string
+	index    int
+	onSelect func(option string)
+}
+
+// NewCycle creates a Cycle with the first of options selected.
+// onSelect is called with the newly selected option whenever the
+// selection got changed by clicking or scrolling.
+func NewCycle(name string, options []string, onSelect func(option string)) *Cycle {
+	return &Cycle{
+		Name:     name,
+		options:  append([]string(nil), options...),
+		onSelect: onSelect,
+	}
+}
+
+// Selected returns the selected option.
+// ok is false if there are no options.
+func (c *Cycle) Selected() (option string, ok bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if len(c.options) == 0 {
+		return "", false
+	}
+	return c.options[c.index], true
+}
+
+// Select selects option without calling onSelect,
+// e.g. if it got selected by another program.
+// Unknown options are ignored.
+func (c *Cycle) Select(option string) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for i, o := range c.options {
+		if o == option {
+			c.index = i
+			return
+		}
+	}
+}
+
+// SetOptions replaces the options. The selected option is kept
+// if it is still available, otherwise the first option is selected.
+func (c *Cycle) SetOptions(options []string) {
+	c.mux.Lock()
+	var selected string
+	if len(c.options) > 0 {
+		selected = c.options[c.index]
+	}
+	c.options = append([]string(nil), options...)
+	c.index = 0
+	c.mux.Unlock()
+	c.Select(selected)
+}
+
+// Handle changes the selection on clicks and scroll events.
+// All other click events are ignored.
+func (c *Cycle) Handle(ev ClickEvent) {
+	var step int
+	switch ev.Button {
+	case LeftClick, ScrollDown:
+		step = 1
+	case RightClick, ScrollUp:
+		step = -1
+	default:
+		return
+	}
+	if ev.Delta > 1 {
+		step *= ev.Delta
+	}
+
+	c.mux.Lock()
+	if len(c.options) < 2 {
+		c.mux.Unlock()
+		return
+	}
+	n := len(c.options)
+	c.index = ((c.index+step)%n + n) % n
+	option := c.options[c.index]
+	c.mux.Unlock()
+
+	if c.onSelect != nil {
+		c.onSelect(option)
+	}
+}
+
+// Block renders the selected option. The block handles its own
+// click events, see Block.OnClick.
+func (c *Cycle) Block() *Block {
+	text, _ := c.Selected()
+	if c.Label != "" {
+		text = c.Label + " " + text
+	}
+	return &Block{
+		Name:     c.Name,
+		Instance: c.Instance,
+		FullText: text,
+		OnClick:  c.Handle,
+	}
+}
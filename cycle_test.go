@@ -0,0 +1,74 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCycle(t *testing.T) {
+	options := []string{"a", "b", "c"}
+
+	tests := []struct {
+		name     string
+		options  []string
+		events   []ClickEvent
+		want     string
+		selected []string
+	}{
+		{"initial", options, nil, "a", nil},
+		{"left click", options, []ClickEvent{{Button: LeftClick}}, "b", []string{"b"}},
+		{"scroll down", options, []ClickEvent{{Button: ScrollDown}, {Button: ScrollDown}}, "c", []string{"b", "c"}},
+		{"wrap forward", options, []ClickEvent{{Button: LeftClick}, {Button: LeftClick}, {Button: LeftClick}}, "a", []string{"b", "c", "a"}},
+		{"wrap backward", options, []ClickEvent{{Button: RightClick}}, "c", []string{"c"}},
+		{"scroll up", options, []ClickEvent{{Button: ScrollUp}, {Button: ScrollUp}}, "b", []string{"c", "b"}},
+		{"coalesced scrolling", options, []ClickEvent{{Button: ScrollDown, Delta: 4}}, "b", []string{"b"}},
+		{"other buttons", options, []ClickEvent{{Button: MiddleClick}}, "a", nil},
+		{"single option", []string{"a"}, []ClickEvent{{Button: LeftClick}}, "a", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selected []string
+			c := NewCycle("profile", tt.options, func(option string) {
+				selected = append(selected, option)
+			})
+			for _, ev := range tt.events {
+				c.Block().OnClick(ev)
+			}
+			if got, ok := c.Selected(); !ok || got != tt.want {
+				t.Errorf("Selected() = %q, %v, want %q", got, ok, tt.want)
+			}
+			if !reflect.DeepEqual(selected, tt.selected) {
+				t.Errorf("selected %q, want %q", selected, tt.selected)
+			}
+		})
+	}
+}
+
+func TestCycleOptions(t *testing.T) {
+	options := []string{"a", "b", "c"}
+	c := NewCycle("profile", options, func(string) { t.Error("onSelect called") })
+	c.Label = "P"
+	// the options of the caller are copied
+	options[0] = "changed"
+
+	c.Select("b")
+	c.Select("unknown")
+	if got := c.Block().FullText; got != "P b" {
+		t.Errorf("got %q, want %q", got, "P b")
+	}
+
+	// the selection is kept if possible
+	c.SetOptions([]string{"c", "b"})
+	if got, _ := c.Selected(); got != "b" {
+		t.Errorf("got %q, want b", got)
+	}
+	c.SetOptions([]string{"x", "y"})
+	if got, _ := c.Selected(); got != "x" {
+		t.Errorf("got %q, want x", got)
+	}
+
+	c.SetOptions(nil)
+	if got, ok := c.Selected(); ok {
+		t.Errorf("got %q without options", got)
+	}
+}
@@ -0,0 +1,76 @@
+package i3bar
+
+// BlockChange describes a block which changed between two status lines.
+type BlockChange struct {
+	Old *Block
+	New *Block
+}
+
+// LineDiff describes the differences between two status lines.
+// Blocks are identified by their name and instance.
+type LineDiff struct {
+	// Added blocks which are only part of the new status line.
+	Added []*Block
+
+	// Removed blocks which are only part of the old status line.
+	Removed []*Block
+
+	// Changed blocks which are part of both status lines but are not Equal.
+	Changed []BlockChange
+}
+
+// Empty reports whether both status lines contain the same blocks.
+// Changes of the order of blocks are not reported.
+func (d LineDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// blockKey identifies a block within a status line. Blocks sharing
+// name and instance are told apart by the order of their occurrence.
+type blockKey struct {
+	name       string
+	instance   string
+	occurrence int
+}
+
+// keyBlocks indexes all blocks of line by their blockKey.
+func keyBlocks(line StatusLine) ([]blockKey, map[blockKey]*Block) {
+	keys := make([]blockKey, 0, len(line))
+	blocks := make(map[blockKey]*Block, len(line))
+	seen := map[[2]string]int{}
+	for _, b := range line {
+		if b == nil {
+			continue
+		}
+		id := [2]string{b.Name, b.Instance}
+		key := blockKey{b.Name, b.Instance, seen[id]}
+		seen[id]++
+		keys = append(keys, key)
+		blocks[key] = b
+	}
+	return keys, blocks
+}
+
+// Diff returns the blocks added, removed and changed from old to new.
+func Diff(old, new StatusLine) LineDiff {
+	var d LineDiff
+	oldKeys, oldBlocks := keyBlocks(old)
+	newKeys, newBlocks := keyBlocks(new)
+
+	for _, key := range oldKeys {
+		if _, ok := newBlocks[key]; !ok {
+			d.Removed = append(d.Removed, oldBlocks[key])
+		}
+	}
+	for _, key := range newKeys {
+		n := newBlocks[key]
+		o, ok := oldBlocks[key]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, n)
+		case !o.Equal(n):
+			d.Changed = append(d.Changed, BlockChange{Old: o, New: n})
+		}
+	}
+	return d
+}
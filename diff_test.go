@@ -0,0 +1,113 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+// blockIDs returns name/instance:text of all blocks.
+func blockIDs(blocks []*Block) []string {
+	var ids []string
+	for _, b := range blocks {
+		ids = append(ids, b.Name+"/"+b.Instance+":"+b.Text())
+	}
+	return ids
+}
+
+// changeIDs returns name/instance:old->new of all changes.
+func changeIDs(changes []BlockChange) []string {
+	var ids []string
+	for _, c := range changes {
+		ids = append(ids, c.New.Name+"/"+c.New.Instance+":"+c.Old.Text()+"->"+c.New.Text())
+	}
+	return ids
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     StatusLine
+		new     StatusLine
+		added   []string
+		removed []string
+		changed []string
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name: "unchanged",
+			old:  StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+			new:  StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+		},
+		{
+			name:  "added",
+			old:   StatusLine{{Name: "a", FullText: "1"}},
+			new:   StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+			added: []string{"b/:2"},
+		},
+		{
+			name:    "removed",
+			old:     StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+			new:     StatusLine{{Name: "b", FullText: "2"}},
+			removed: []string{"a/:1"},
+		},
+		{
+			name:    "changed",
+			old:     StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+			new:     StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "3"}},
+			changed: []string{"b/:2->3"},
+		},
+		{
+			// the order of blocks is ignored
+			name: "reordered",
+			old:  StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}},
+			new:  StatusLine{{Name: "b", FullText: "2"}, {Name: "a", FullText: "1"}},
+		},
+		{
+			name:    "instances",
+			old:     StatusLine{{Name: "disk", Instance: "/", FullText: "1"}, {Name: "disk", Instance: "/home", FullText: "2"}},
+			new:     StatusLine{{Name: "disk", Instance: "/", FullText: "3"}, {Name: "disk", Instance: "/boot", FullText: "4"}},
+			added:   []string{"disk//boot:4"},
+			removed: []string{"disk//home:2"},
+			changed: []string{"disk//:1->3"},
+		},
+		{
+			// blocks sharing name and instance are matched in order
+			name:    "duplicate names",
+			old:     StatusLine{{FullText: "1"}, {FullText: "2"}},
+			new:     StatusLine{{FullText: "1"}, {FullText: "3"}, {FullText: "4"}},
+			added:   []string{"/:4"},
+			changed: []string{"/:2->3"},
+		},
+		{
+			// blocks are compared by Equal
+			name: "equal values",
+			old:  StatusLine{{Name: "a", FullText: "1", Color: "#fff", Separator: Bool(true)}},
+			new:  StatusLine{{Name: "a", LazyText: TextFunc(func() string { return "1" }), Color: "white"}},
+		},
+		{
+			name:    "nil blocks",
+			old:     StatusLine{nil, {Name: "a", FullText: "1"}},
+			new:     StatusLine{{Name: "a", FullText: "1", Urgent: true}, nil},
+			changed: []string{"a/:1->1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diff(tt.old, tt.new)
+			if got := blockIDs(d.Added); !reflect.DeepEqual(got, tt.added) {
+				t.Errorf("added %v, want %v", got, tt.added)
+			}
+			if got := blockIDs(d.Removed); !reflect.DeepEqual(got, tt.removed) {
+				t.Errorf("removed %v, want %v", got, tt.removed)
+			}
+			if got := changeIDs(d.Changed); !reflect.DeepEqual(got, tt.changed) {
+				t.Errorf("changed %v, want %v", got, tt.changed)
+			}
+			if empty := tt.added == nil && tt.removed == nil && tt.changed == nil; d.Empty() != empty {
+				t.Errorf("Empty() = %v, want %v", d.Empty(), empty)
+			}
+		})
+	}
+}
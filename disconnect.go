@@ -0,0 +1,42 @@
+package i3bar
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// OnDisconnect registers fn to be called once i3bar went away.
+// This is detected by a broken pipe while writing to the stream.
+// The stream is closed and its goroutines, e.g. the click event reader,
+// are stopped before fn is called with the write error, so fn may exit
+// the program, set up a new Stream or reconnect the stream with Reconnect.
+// fn is called at most once per registration.
+//
+// This function is thread safe.
+func (s *Stream) OnDisconnect(fn func(err error)) {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.onDisconnect = fn
+}
+
+// checkWrite inspects a failed write and closes the stream
+// and stops its goroutines if the failure is caused by a broken pipe.
+// The caller has to hold wMux.
+func (s *Stream) checkWrite(err error) error {
+	if !errors.Is(err, syscall.EPIPE) {
+		return err
+	}
+	err = withKind(ErrDisconnected, err)
+
+	// i3bar is gone, there is no one left to close the array for.
+	// Unlike Close this does not prevent a Reconnect.
+	s.closed = true
+	s.stop()
+
+	if fn := s.onDisconnect; fn != nil {
+		s.onDisconnect = nil
+		go fn(err)
+	}
+	return err
+}
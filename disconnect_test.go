@@ -0,0 +1,102 @@
+package i3bar
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// brokenPipe returns the write end of a pipe whose read end is closed,
+// so that writing to it fails with EPIPE once the pipe buffer is flushed.
+func brokenPipe(t *testing.T) (w *os.File, disconnect func()) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		r.Close()
+		w.Close()
+	})
+	return w, func() { r.Close() }
+}
+
+func TestDisconnect(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"sync", nil},
+		{"async", []Option{WithAsync(100)}},
+		{"blink", []Option{WithBlink(time.Millisecond, "#000000", "")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, disconnect := brokenPipe(t)
+			// i3bar keeps its end of the click event pipe open
+			clicks, _ := io.Pipe()
+			defer clicks.Close()
+
+			h := DefaultHeader()
+			h.ClickEvents = true
+			s, err := NewStream(w, clicks, false, h, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			disconnected := make(chan error, 1)
+			s.OnDisconnect(func(err error) { disconnected <- err })
+
+			disconnect()
+			line := StatusLine{{FullText: "a", Urgent: true}}
+			if err := s.SendLine(line); err != nil && !errors.Is(err, ErrDisconnected) {
+				t.Fatalf("SendLine: %v", err)
+			}
+
+			select {
+			case err := <-disconnected:
+				if !errors.Is(err, ErrDisconnected) {
+					t.Errorf("OnDisconnect got %v, want ErrDisconnected", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("OnDisconnect was not called")
+			}
+
+			// the click event reader is stopped although the reader stays open
+			select {
+			case _, ok := <-s.Events():
+				if ok {
+					t.Error("unexpected click event")
+				}
+			case <-time.After(time.Second):
+				t.Fatal("click event reader was not stopped")
+			}
+			if err := s.SendLine(line); !errors.Is(err, ErrClosed) {
+				t.Errorf("SendLine after disconnect: got %v, want ErrClosed", err)
+			}
+
+			// reconnecting restarts the stream and its goroutines
+			var out bytes.Buffer
+			if err := s.Reconnect(&out, nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.SendLine(StatusLine{{FullText: "b"}}); err != nil {
+				t.Fatalf("SendLine after reconnect: %v", err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); !strings.Contains(got, `"full_text":"b"`) {
+				t.Errorf("status line not sent after reconnect: %q", got)
+			}
+			if err := s.Reconnect(&bytes.Buffer{}, nil); !errors.Is(err, ErrClosed) {
+				t.Errorf("Reconnect after Close: got %v, want ErrClosed", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package i3bar
+
+import (
+	"time"
+)
+
+// DefaultDoubleClickWindow is the time window used by WithDoubleClick
+// if no window is given.
+const DefaultDoubleClickWindow = 300 * time.Millisecond
+
+// DoubleClick returns a click handler which calls double for double clicks
+// detected by WithDoubleClick and single for all other click events.
+// Either handler may be nil.
+func DoubleClick(single, double func(ev ClickEvent)) func(ev ClickEvent) {
+	return func(ev ClickEvent) {
+		fn := single
+		if ev.Double {
+			fn = double
+		}
+		if fn != nil {
+			fn(ev)
+		}
+	}
+}
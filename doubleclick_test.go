@@ -0,0 +1,105 @@
+package i3bar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithDoubleClick(t *testing.T) {
+	tests := []struct {
+		name   string
+		clicks string
+		want   string
+	}{
+		{"single", "a1", "a1"},
+		{"double", "a1 a1", "a1 double"},
+		{"triple", "a1 a1 a1", "a1 double, a1"},
+		{"other button", "a1 a3", "a1, a3"},
+		{"other block", "a1 b1", "a1, b1"},
+		{"double after other block", "a1 b1 b1", "a1, b1 double"},
+		{"scroll", "a1 a4 a1", "a1, a4, a1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stream bytes.Buffer
+			stream.WriteString("[\n")
+			for _, c := range strings.Fields(tt.clicks) {
+				fmt.Fprintf(&stream, "{\"name\":%q,\"button\":%c},\n", c[:1], c[1])
+			}
+			s, err := NewStream(&bytes.Buffer{}, &stream, false, DefaultHeader(),
+				WithClickEvents(), WithDoubleClick(time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			// held clicks are delivered once the stream ends
+			var got []string
+			for ev := range s.Events() {
+				desc := fmt.Sprintf("%s%d", ev.Name, ev.Button)
+				if ev.Double {
+					desc += " double"
+				}
+				got = append(got, desc)
+			}
+			if strings.Join(got, ", ") != tt.want {
+				t.Errorf("got %q, want %q", strings.Join(got, ", "), tt.want)
+			}
+		})
+	}
+}
+
+func TestDoubleClickHandler(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	window := 50 * time.Millisecond
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(),
+		WithClickEvents(), WithDoubleClick(window), WithAsyncClicks(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	clicks := make(chan string, 2)
+	err = s.OnClick("a", "", DoubleClick(
+		func(ClickEvent) { clicks <- "single" },
+		func(ClickEvent) { clicks <- "double" },
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	io.WriteString(w, "[\n{\"name\":\"a\",\"button\":1}\n")
+	select {
+	case got := <-clicks:
+		if got != "single" {
+			t.Errorf("got %s click, want single", got)
+		}
+		if elapsed := time.Since(start); elapsed < window {
+			t.Errorf("single click delivered after %v, before the window of %v passed", elapsed, window)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("single click not delivered")
+	}
+
+	io.WriteString(w, ",{\"name\":\"a\",\"button\":1}\n,{\"name\":\"a\",\"button\":1}\n")
+	select {
+	case got := <-clicks:
+		if got != "double" {
+			t.Errorf("got %s click, want double", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("double click not delivered")
+	}
+
+	// synthesized clicks go through the click queues and metrics
+	waitFor(t, "handled clicks", func() bool { return s.ClickStats("a", "").Handled == 2 })
+	if stats := s.ClickStats("a", ""); stats.Received != 2 || stats.Dispatched != 2 {
+		t.Errorf("got %+v", stats)
+	}
+}
@@ -0,0 +1,72 @@
+package i3bar
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationStyle selects how HumanDuration formats durations.
+type DurationStyle int
+
+const (
+	// DurationSpaced formats the two largest units separated by a space, e.g. "2h 13m".
+	DurationSpaced DurationStyle = iota
+	// DurationCompact formats the two largest units without separator, e.g. "3d4h".
+	DurationCompact
+	// DurationClock formats hours, minutes and seconds like a clock, e.g. "02:13:45".
+	DurationClock
+)
+
+// durationUnits used by the spaced and compact styles in descending order.
+var durationUnits = []struct {
+	suffix string
+	length time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// HumanDuration formats d in the given style, e.g. for uptime,
+// remaining battery time or timers. d is truncated to full seconds.
+func HumanDuration(d time.Duration, style DurationStyle) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	d = d.Truncate(time.Second)
+
+	if style == DurationClock {
+		h, m, s := d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second
+		return fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, s)
+	}
+
+	// the largest unit and the one following it unless it is zero
+	var parts []string
+	for i, unit := range durationUnits {
+		n := d / unit.length
+		if n == 0 {
+			continue
+		}
+		parts = append(parts, strconv.FormatInt(int64(n), 10)+unit.suffix)
+		if i+1 < len(durationUnits) {
+			next := durationUnits[i+1]
+			if m := d % unit.length / next.length; m > 0 {
+				parts = append(parts, strconv.FormatInt(int64(m), 10)+next.suffix)
+			}
+		}
+		break
+	}
+	if len(parts) == 0 {
+		return "0s"
+	}
+
+	sep := " "
+	if style == DurationCompact {
+		sep = ""
+	}
+	return sign + strings.Join(parts, sep)
+}
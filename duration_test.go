@@ -0,0 +1,38 @@
+package i3bar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanDuration(t *testing.T) {
+	d := 2*time.Hour + 13*time.Minute + 45*time.Second
+
+	tests := []struct {
+		name  string
+		d     time.Duration
+		style DurationStyle
+		want  string
+	}{
+		{"spaced", d, DurationSpaced, "2h 13m"},
+		{"compact", d, DurationCompact, "2h13m"},
+		{"clock", d, DurationClock, "02:13:45"},
+		{"days", 3*24*time.Hour + 4*time.Hour + 5*time.Minute, DurationCompact, "3d4h"},
+		{"zero next unit", 3 * 24 * time.Hour, DurationSpaced, "3d"},
+		{"skipped unit", 24*time.Hour + 5*time.Minute, DurationSpaced, "1d"},
+		{"seconds", 42 * time.Second, DurationSpaced, "42s"},
+		{"truncated", 1999 * time.Millisecond, DurationSpaced, "1s"},
+		{"zero", 0, DurationSpaced, "0s"},
+		{"below a second", 500 * time.Millisecond, DurationCompact, "0s"},
+		{"negative", -d, DurationSpaced, "-2h 13m"},
+		{"negative clock", -d, DurationClock, "-02:13:45"},
+		{"clock beyond a day", 27 * time.Hour, DurationClock, "27:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HumanDuration(tt.d, tt.style); got != tt.want {
+				t.Errorf("HumanDuration(%v) = %q, want %q", tt.d, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package i3bar
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// elementReader splits an infinite json array into its raw elements
+// without decoding them, so that a malformed element does not prevent
+// reading the elements following it.
+//
+// Bar implementations differ in framing: the opening bracket may be on a
+// line of its own, commas may lead or trail elements and whitespace varies.
+// Besides that, newline delimited elements without brackets and commas
+// are accepted as well.
+type elementReader struct {
+	r       *bufio.Reader
+	offset  int64
+	started bool
+	ended   bool
+}
+
+// newElementReader creates an elementReader on r.
+func newElementReader(r io.Reader) *elementReader {
+	return &elementReader{r: bufio.NewReader(r)}
+}
+
+// start consumes the opening bracket of the array if there is one.
+func (er *elementReader) start() error {
+	for {
+		c, err := er.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		er.offset++
+		if isBlank(c) {
+			continue
+		}
+		switch c {
+		case '[':
+		case '{':
+			// newline delimited elements
+			er.r.UnreadByte()
+			er.offset--
+		default:
+			return withKind(ErrProtocol, errors.Errorf("unexpected start of click event array: %q", c))
+		}
+		er.started = true
+		return nil
+	}
+}
+
+// isBlank reports whether c is whitespace or part of a
+// byte order mark, which may precede the array.
+func isBlank(c byte) bool {
+	return unicode.IsSpace(rune(c)) || c == 0xef || c == 0xbb || c == 0xbf
+}
+
+// next returns the next raw element of the array and its offset.
+// Empty elements, e.g. caused by duplicate commas, are skipped.
+// next returns io.EOF once the array is closed or the reader is exhausted.
+// Incomplete elements at the end of the stream are dropped.
+func (er *elementReader) next() ([]byte, int64, error) {
+	if !er.started {
+		if err := er.start(); err != nil {
+			return nil, er.offset, err
+		}
+	}
+
+	for !er.ended {
+		elem, offset, err := er.element()
+		if err != nil {
+			return nil, offset, err
+		}
+		if len(elem) > 0 {
+			return elem, offset, nil
+		}
+	}
+	return nil, er.offset, io.EOF
+}
+
+// element reads up to the next comma, the closing bracket or
+// the end of the line following a complete value outside of
+// nested values and strings.
+func (er *elementReader) element() ([]byte, int64, error) {
+	var buf []byte
+	offset := er.offset
+	depth := 0
+	inString, escaped := false, false
+	for {
+		c, err := er.r.ReadByte()
+		if err == io.EOF && depth == 0 && !inString && len(bytes.TrimSpace(buf)) > 0 {
+			// the last element is complete, but not terminated
+			er.ended = true
+			c, err = '\n', nil
+		}
+		if err != nil {
+			return nil, offset, err
+		}
+		er.offset++
+
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case inString && c == '"':
+			inString = false
+		case inString:
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case (c == '}' || c == ']') && depth > 0:
+			depth--
+		case c == ']':
+			er.ended = true
+			fallthrough
+		case c == ',' && depth == 0,
+			c == '\n' && depth == 0 && len(bytes.TrimSpace(buf)) > 0:
+			// leading whitespace does not belong to the element
+			offset += int64(len(buf) - len(bytes.TrimLeftFunc(buf, unicode.IsSpace)))
+			return bytes.TrimSpace(buf), offset, nil
+		}
+		buf = append(buf, c)
+	}
+}
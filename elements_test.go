@@ -0,0 +1,91 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// TestClickStreamGolden decodes the click event streams captured from
+// i3bar and swaybar in testdata/clicks and compares the received events
+// and errors with the golden files next to them.
+// Run go test -run ^TestClickStreamGolden$ -update to update them.
+func TestClickStreamGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "clicks", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no click streams in testdata/clicks")
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+		t.Run(name, func(t *testing.T) {
+			stream, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := decodeClickStream(t, stream)
+
+			golden := strings.TrimSuffix(input, ".json") + ".golden"
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+// decodeClickStream reads the click events of stream through a Stream
+// and returns one line per received event followed by one line per
+// reported error.
+func decodeClickStream(t *testing.T, stream []byte) []byte {
+	t.Helper()
+	s, err := NewStream(&bytes.Buffer{}, bytes.NewReader(stream), false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var out bytes.Buffer
+	for ev := range s.Events() {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&out, "event: %s\n", data)
+	}
+	// all errors are reported once the events channel is closed
+	for {
+		select {
+		case err := <-s.Errors():
+			var derr *DecodeError
+			if !errors.As(err, &derr) {
+				fmt.Fprintf(&out, "error: %v\n", err)
+				continue
+			}
+			end := derr.Offset + int64(len(derr.Raw))
+			if end > int64(len(stream)) || !bytes.Equal(stream[derr.Offset:end], derr.Raw) {
+				t.Errorf("offset %d does not point to %s", derr.Offset, derr.Raw)
+			}
+			fmt.Fprintf(&out, "decode error: offset %d: %s: %v\n", derr.Offset, derr.Raw, derr.Err)
+		default:
+			return out.Bytes()
+		}
+	}
+}
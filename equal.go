@@ -0,0 +1,117 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// defaultBorderWidth used by i3bar if a border width is unset.
+const defaultBorderWidth = 1
+
+// Equal reports whether b and other look the same to i3bar.
+// Unset optional fields are treated like their i3bar defaults,
+// e.g. an unset Separator equals Bool(true), and colors are
+// compared by value, e.g. "#fff" equals "white".
+// Lazy texts are evaluated for comparison.
+func (b *Block) Equal(other *Block) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	return b.Name == other.Name &&
+		b.Instance == other.Instance &&
+		b.Text() == other.Text() &&
+		b.ShortText == other.ShortText &&
+		equalColor(b.Color, other.Color) &&
+		equalColor(b.Background, other.Background) &&
+		equalColor(b.Border, other.Border) &&
+		equalInt(b.BorderTop, other.BorderTop, defaultBorderWidth) &&
+		equalInt(b.BorderRight, other.BorderRight, defaultBorderWidth) &&
+		equalInt(b.BorderBottom, other.BorderBottom, defaultBorderWidth) &&
+		equalInt(b.BorderLeft, other.BorderLeft, defaultBorderWidth) &&
+		equalMinWidth(b.MinWidth, other.MinWidth) &&
+		b.Align == other.Align &&
+		b.Urgent == other.Urgent &&
+		equalBool(b.Separator, other.Separator, true) &&
+		b.SeparatorBlockWidth == other.SeparatorBlockWidth &&
+		b.Markup == other.Markup &&
+		equalExtra(b.Extra, other.Extra)
+}
+
+// Equal reports whether both status lines contain equal blocks in the same order.
+func (l StatusLine) Equal(other StatusLine) bool {
+	if len(l) != len(other) {
+		return false
+	}
+	for i := range l {
+		if !l[i].Equal(other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalColor compares two colors by value.
+// Invalid colors are compared literally.
+func equalColor(a, b Color) bool {
+	if a == b {
+		return true
+	}
+	ca, errA := ParseColor(string(a))
+	cb, errB := ParseColor(string(b))
+	if errA != nil || errB != nil {
+		return false
+	}
+	return ca.WithoutAlpha() == cb.WithoutAlpha() && alphaOf(ca) == alphaOf(cb)
+}
+
+// alphaOf returns the alpha component of a valid color.
+func alphaOf(c Color) uint8 {
+	_, _, _, a, _ := c.RGBA()
+	return a
+}
+
+// equalInt compares two optional integers, treating unset ones as def.
+func equalInt(a, b *int, def int) bool {
+	va, vb := def, def
+	if a != nil {
+		va = *a
+	}
+	if b != nil {
+		vb = *b
+	}
+	return va == vb
+}
+
+// equalBool compares two optional booleans, treating unset ones as def.
+func equalBool(a, b *bool, def bool) bool {
+	va, vb := def, def
+	if a != nil {
+		va = *a
+	}
+	if b != nil {
+		vb = *b
+	}
+	return va == vb
+}
+
+// equalMinWidth compares two optional minimum widths by their encoding.
+func equalMinWidth(a, b *MinWidth) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Text != "" || b.Text != "" {
+		return a.Text == b.Text
+	}
+	return a.Pixels == b.Pixels
+}
+
+// equalExtra compares extra keys by their encoding,
+// so that e.g. int(1) equals float64(1).
+func equalExtra(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	ea, errA := json.Marshal(a)
+	eb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ea, eb)
+}
@@ -0,0 +1,76 @@
+package i3bar
+
+import "testing"
+
+func TestBlockEqual(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  *Block
+		equal bool
+	}{
+		{"both nil", nil, nil, true},
+		{"one nil", &Block{}, nil, false},
+		{"same text", &Block{FullText: "a"}, &Block{FullText: "a"}, true},
+		{"different text", &Block{FullText: "a"}, &Block{FullText: "b"}, false},
+		{"lazy text", &Block{FullText: "a"}, &Block{LazyText: TextFunc(func() string { return "a" })}, true},
+		{"different instance", &Block{Name: "a", Instance: "0"}, &Block{Name: "a", Instance: "1"}, false},
+		// colors are compared by value
+		{"color notation", &Block{Color: "#fff"}, &Block{Color: "#ffffff"}, true},
+		{"color name", &Block{Color: "white"}, &Block{Color: "#FFFFFF"}, true},
+		{"opaque alpha", &Block{Color: "#ffffff"}, &Block{Color: "#ffffffff"}, true},
+		{"alpha", &Block{Color: "#ffffff"}, &Block{Color: "#ffffff80"}, false},
+		{"different background", &Block{Background: "#000"}, &Block{Background: "#001"}, false},
+		{"invalid colors", &Block{Color: "nope"}, &Block{Color: "nope"}, true},
+		// unset optional fields equal the i3bar defaults
+		{"default border", &Block{}, &Block{BorderTop: Int(1), BorderLeft: Int(1)}, true},
+		{"hidden border", &Block{}, &Block{BorderBottom: Int(0)}, false},
+		{"default separator", &Block{}, &Block{Separator: Bool(true)}, true},
+		{"no separator", &Block{}, &Block{Separator: Bool(false)}, false},
+		{"min width", &Block{MinWidth: MinWidthPixels(10)}, &Block{MinWidth: MinWidthPixels(10)}, true},
+		{"min width pixels and text", &Block{MinWidth: MinWidthPixels(10)}, &Block{MinWidth: MinWidthText("10")}, false},
+		{"unset min width", &Block{}, &Block{MinWidth: MinWidthPixels(0)}, false},
+		{"urgent", &Block{}, &Block{Urgent: true}, false},
+		{"markup", &Block{}, &Block{Markup: Pango}, false},
+		// extra keys are compared by their encoding
+		{"extra numbers", &Block{Extra: map[string]interface{}{"_n": 1}}, &Block{Extra: map[string]interface{}{"_n": 1.0}}, true},
+		{"empty extra", &Block{}, &Block{Extra: map[string]interface{}{}}, true},
+		{"different extra", &Block{Extra: map[string]interface{}{"_n": 1}}, &Block{Extra: map[string]interface{}{"_n": 2}}, false},
+		// click handlers are not part of the protocol
+		{"click handler", &Block{}, &Block{OnClick: func(ClickEvent) {}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.equal {
+				t.Errorf("a.Equal(b) = %v, want %v", got, tt.equal)
+			}
+			if got := tt.b.Equal(tt.a); got != tt.equal {
+				t.Errorf("b.Equal(a) = %v, want %v", got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestStatusLineEqual(t *testing.T) {
+	line := StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}}
+	tests := []struct {
+		name  string
+		other StatusLine
+		equal bool
+	}{
+		{"equal", StatusLine{{Name: "a", FullText: "1", Separator: Bool(true)}, {Name: "b", FullText: "2"}}, true},
+		{"reordered", StatusLine{{Name: "b", FullText: "2"}, {Name: "a", FullText: "1"}}, false},
+		{"shorter", StatusLine{{Name: "a", FullText: "1"}}, false},
+		{"nil block", StatusLine{{Name: "a", FullText: "1"}, nil}, false},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := line.Equal(tt.other); got != tt.equal {
+				t.Errorf("Equal() = %v, want %v", got, tt.equal)
+			}
+		})
+	}
+	if !(StatusLine{}).Equal(nil) {
+		t.Error("empty status line does not equal nil")
+	}
+}
@@ -0,0 +1,80 @@
+package i3bar
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrClosed is returned when using a Stream which has already been closed.
+	ErrClosed = errors.New("stream closed")
+
+	// ErrDisconnected is returned when i3bar went away, e.g. because
+	// it exited or restarted and writing to it failed with a broken pipe.
+	ErrDisconnected = errors.New("disconnected")
+
+	// ErrEncode is returned when a value could not be encoded into json,
+	// e.g. because a Block contains an invalid value.
+	ErrEncode = errors.New("failed to encode")
+
+	// ErrProtocol is returned when either side violates the i3bar protocol.
+	ErrProtocol = errors.New("protocol violation")
+
+	// ErrClickEventsDisabled is returned when registering a click handler
+	// on a Stream whose header does not enable click events.
+	// See WithClickEvents.
+	ErrClickEventsDisabled = errors.New("click events are not enabled")
+)
+
+// DecodeError is returned when a click event sent by i3bar could not be decoded.
+type DecodeError struct {
+	// Offset in the click event stream where decoding failed.
+	Offset int64
+
+	// Raw click event which failed to decode.
+	Raw []byte
+
+	// Err is the underlying decoding error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode click event at offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// kindError annotates err with one of the sentinel errors,
+// so that errors.Is matches both the sentinel and the cause chain of err.
+type kindError struct {
+	kind error
+	err  error
+}
+
+// withKind annotates err with the sentinel error kind.
+func withKind(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// Error implements the error interface.
+func (e *kindError) Error() string {
+	return e.kind.Error() + ": " + e.err.Error()
+}
+
+// Unwrap returns the annotated error.
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the sentinel error of e.
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
@@ -0,0 +1,70 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestErrorKinds(t *testing.T) {
+	cause := errors.New("cause")
+	tests := []struct {
+		name string
+		err  error
+		is   []error
+		not  []error
+		msg  string
+	}{
+		{"kind", withKind(ErrProtocol, cause), []error{ErrProtocol, cause}, []error{ErrEncode}, "protocol violation: cause"},
+		{"wrapped kind", errors.Wrap(withKind(ErrEncode, cause), "context"), []error{ErrEncode, cause}, []error{ErrProtocol}, "context: failed to encode: cause"},
+		{"nested kinds", withKind(ErrDisconnected, withKind(ErrProtocol, cause)), []error{ErrDisconnected, ErrProtocol, cause}, []error{ErrClosed}, "disconnected: protocol violation: cause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, target := range tt.is {
+				if !errors.Is(tt.err, target) {
+					t.Errorf("errors.Is(%v, %v) = false", tt.err, target)
+				}
+			}
+			for _, target := range tt.not {
+				if errors.Is(tt.err, target) {
+					t.Errorf("errors.Is(%v, %v) = true", tt.err, target)
+				}
+			}
+			if tt.err.Error() != tt.msg {
+				t.Errorf("got %q, want %q", tt.err.Error(), tt.msg)
+			}
+		})
+	}
+	if err := withKind(ErrProtocol, nil); err != nil {
+		t.Errorf("withKind(nil) = %v, want nil", err)
+	}
+}
+
+func TestStreamErrorKinds(t *testing.T) {
+	if _, err := NewStream(&bytes.Buffer{}, nil, false, Header{Version: 0}); !errors.Is(err, ErrProtocol) {
+		t.Errorf("invalid version: got %v, want ErrProtocol", err)
+	}
+
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.SendLine(StatusLine{{FullText: "a", Color: "invalid"}})
+	if !errors.Is(err, ErrEncode) {
+		t.Errorf("invalid color: got %v, want ErrEncode", err)
+	}
+	s.Close()
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); !errors.Is(err, ErrClosed) {
+		t.Errorf("closed stream: got %v, want ErrClosed", err)
+	}
+
+	var derr *DecodeError
+	syntax := &json.SyntaxError{}
+	err = errors.Wrap(&DecodeError{Offset: 3, Raw: []byte("{"), Err: syntax}, "context")
+	if !errors.As(err, &derr) || derr.Offset != 3 || !errors.As(err, &syntax) {
+		t.Errorf("got %v, want DecodeError wrapping a SyntaxError", err)
+	}
+}
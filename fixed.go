@@ -0,0 +1,66 @@
+package i3bar
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FigureSpace is a space as wide as a digit in most fonts.
+const FigureSpace = "\u2007"
+
+// NumberPadding is how FixedNumber pads numbers to their width.
+type NumberPadding int
+
+const (
+	// PadFigureSpace pads numbers on the left with figure spaces.
+	PadFigureSpace NumberPadding = iota
+	// PadZero pads numbers with leading zeros after the sign.
+	PadZero
+)
+
+// FixedNumber formats v with precision decimals and pads it to width
+// characters including sign and decimal point, so that the text keeps
+// its width while the value changes. Use a monospace font or FixedMinWidth
+// for fonts without tabular digits. Wider numbers are not truncated.
+func FixedNumber(v float64, width, precision int, pad NumberPadding) string {
+	s := strconv.FormatFloat(v, 'f', precision, 64)
+	missing := width - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return s
+	}
+	if pad == PadZero {
+		sign := ""
+		if strings.HasPrefix(s, "-") {
+			sign, s = "-", s[1:]
+		}
+		return sign + strings.Repeat("0", missing) + s
+	}
+	return strings.Repeat(FigureSpace, missing) + s
+}
+
+// FixedPercent formats percent as whole number padded to three
+// characters followed by "%", e.g. "  7%" or " 42%".
+func FixedPercent(percent float64, pad NumberPadding) string {
+	return FixedNumber(percent, 3, 0, pad) + "%"
+}
+
+// FixedSample returns the widest text FixedNumber renders for width and
+// precision followed by suffix, e.g. "000.0%", to be measured for MinWidth.
+func FixedSample(width, precision int, suffix string) string {
+	if precision <= 0 {
+		return strings.Repeat("0", width) + suffix
+	}
+	digits := width - precision - 1
+	if digits < 1 {
+		digits = 1
+	}
+	return strings.Repeat("0", digits) + "." + strings.Repeat("0", precision) + suffix
+}
+
+// FixedMinWidth creates a MinWidth fitting every number FixedNumber renders
+// for width and precision followed by suffix, so that the block keeps its
+// width even if the digits of the font differ in width.
+func FixedMinWidth(width, precision int, suffix string) *MinWidth {
+	return MinWidthText(FixedSample(width, precision, suffix))
+}
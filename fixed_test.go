@@ -0,0 +1,82 @@
+package i3bar
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFixedNumber(t *testing.T) {
+	const fs = FigureSpace
+
+	tests := []struct {
+		name      string
+		v         float64
+		width     int
+		precision int
+		pad       NumberPadding
+		want      string
+	}{
+		{"figure space", 7, 3, 0, PadFigureSpace, fs + fs + "7"},
+		{"zero", 7, 3, 0, PadZero, "007"},
+		{"decimals", 3.14159, 5, 2, PadFigureSpace, fs + "3.14"},
+		{"zero with decimals", 3.14159, 5, 2, PadZero, "03.14"},
+		{"negative", -4.5, 5, 1, PadFigureSpace, fs + "-4.5"},
+		// zeros are padded after the sign
+		{"negative zero padded", -4.5, 5, 1, PadZero, "-04.5"},
+		{"rounded", 99.96, 4, 1, PadZero, "100.0"},
+		{"exact width", 123, 3, 0, PadZero, "123"},
+		// wider numbers are not truncated
+		{"too wide", 12345, 3, 0, PadFigureSpace, "12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FixedNumber(tt.v, tt.width, tt.precision, tt.pad)
+			if got != tt.want {
+				t.Errorf("FixedNumber(%v, %d, %d) = %q, want %q", tt.v, tt.width, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixedPercent(t *testing.T) {
+	tests := []struct {
+		percent float64
+		pad     NumberPadding
+		want    string
+	}{
+		{7, PadFigureSpace, FigureSpace + FigureSpace + "7%"},
+		{42.4, PadZero, "042%"},
+		{100, PadFigureSpace, "100%"},
+	}
+	for _, tt := range tests {
+		if got := FixedPercent(tt.percent, tt.pad); got != tt.want {
+			t.Errorf("FixedPercent(%v) = %q, want %q", tt.percent, got, tt.want)
+		}
+		// every percentage has the width of the sample
+		if got, want := utf8.RuneCountInString(FixedPercent(tt.percent, tt.pad)), len(FixedSample(3, 0, "%")); got != want {
+			t.Errorf("FixedPercent(%v) has %d characters, want %d", tt.percent, got, want)
+		}
+	}
+}
+
+func TestFixedSample(t *testing.T) {
+	tests := []struct {
+		width, precision int
+		suffix           string
+		want             string
+	}{
+		{3, 0, "%", "000%"},
+		{5, 1, "%", "000.0%"},
+		{4, 2, "", "0.00"},
+		// at least one digit precedes the decimal point
+		{2, 2, "", "0.00"},
+	}
+	for _, tt := range tests {
+		if got := FixedSample(tt.width, tt.precision, tt.suffix); got != tt.want {
+			t.Errorf("FixedSample(%d, %d, %q) = %q, want %q", tt.width, tt.precision, tt.suffix, got, tt.want)
+		}
+	}
+	if got := FixedMinWidth(5, 1, "%"); got.Text != "000.0%" {
+		t.Errorf("FixedMinWidth = %+v, want sample 000.0%%", got)
+	}
+}
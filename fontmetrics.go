@@ -0,0 +1,290 @@
+package i3bar
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDPI is used to convert font sizes in points to pixels.
+const DefaultDPI = 96
+
+// FontMetrics measures texts using the advance widths of the glyphs
+// of a TrueType or OpenType font. Kerning and font fallback are not
+// taken into account, so widths are estimates suitable for MinWidth.
+//
+// Note that i3bar measures a MinWidthText with its own font, which
+// should be preferred if the block uses the font of the bar.
+type FontMetrics struct {
+	// Size of the font in pixels.
+	Size float64
+
+	unitsPerEm float64
+	advances   []uint16
+	glyph      func(r rune) uint16
+}
+
+// LoadFont reads the metrics of the TrueType or OpenType font at path
+// with size pixels. For font collections the first font is used.
+func LoadFont(path string, size float64) (*FontMetrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read font")
+	}
+	f, err := parseFont(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse font %s", path)
+	}
+	f.Size = size
+	return f, nil
+}
+
+// LoadFontDescription reads the metrics of the font matching an i3 font
+// description like "pango:DejaVu Sans Mono 10" using fontconfig.
+// Sizes in points are converted to pixels using dpi, which defaults
+// to DefaultDPI if not positive. Sizes in pixels like "12px" are used as is.
+func LoadFontDescription(desc string, dpi float64) (*FontMetrics, error) {
+	fields := strings.Fields(strings.TrimPrefix(desc, "pango:"))
+	if len(fields) < 2 || strings.HasPrefix(desc, "-") {
+		return nil, errors.Errorf("unsupported font description: %s", desc)
+	}
+	if dpi <= 0 {
+		dpi = DefaultDPI
+	}
+
+	size := fields[len(fields)-1]
+	pixels := strings.HasSuffix(size, "px")
+	n, err := strconv.ParseFloat(strings.TrimSuffix(size, "px"), 64)
+	if err != nil {
+		return nil, errors.Errorf("invalid font size: %s", size)
+	}
+	if !pixels {
+		n = n * dpi / 72
+	}
+
+	// only the first of multiple families is measured
+	family := strings.Join(fields[:len(fields)-1], " ")
+	family = strings.TrimSpace(strings.Split(family, ",")[0])
+	path, err := MatchFont(family)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFont(path, n)
+}
+
+// MatchFont returns the path of the font file best matching the
+// fontconfig pattern, e.g. "DejaVu Sans Mono:bold".
+func MatchFont(pattern string) (string, error) {
+	out, err := exec.Command("fc-match", "--format=%{file}", pattern).Output()
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to match font")
+	}
+	path := strings.TrimSpace(string(out))
+	if path == "" {
+		return "", errors.Errorf("no font matches %s", pattern)
+	}
+	return path, nil
+}
+
+// Width returns the estimated width of text in pixels.
+func (f *FontMetrics) Width(text string) int {
+	units := 0.0
+	for _, r := range text {
+		units += float64(f.advance(f.glyph(r)))
+	}
+	return int(math.Ceil(units * f.Size / f.unitsPerEm))
+}
+
+// MinWidth creates a MinWidth with the width of sample in pixels,
+// e.g. "100%" for a percentage, so that the block keeps its width
+// while the value changes.
+func (f *FontMetrics) MinWidth(sample string) *MinWidth {
+	return MinWidthPixels(f.Width(sample))
+}
+
+// advance returns the advance width of glyph in font units.
+func (f *FontMetrics) advance(glyph uint16) uint16 {
+	if int(glyph) < len(f.advances) {
+		return f.advances[glyph]
+	}
+	// glyphs without metrics share the advance width of the last metric
+	return f.advances[len(f.advances)-1]
+}
+
+// parseFont reads the metrics of a TrueType or OpenType font
+// or of the first font of a font collection.
+func parseFont(data []byte) (*FontMetrics, error) {
+	if len(data) >= 16 && string(data[:4]) == "ttcf" {
+		offset := binary.BigEndian.Uint32(data[12:])
+		if int64(offset) >= int64(len(data)) {
+			return nil, errors.New("invalid font collection")
+		}
+		return parseFontAt(data, offset)
+	}
+	return parseFontAt(data, 0)
+}
+
+// parseFontAt reads the metrics of the font with its table directory at offset.
+func parseFontAt(data []byte, offset uint32) (*FontMetrics, error) {
+	tables := map[string][]byte{}
+	dir := data[offset:]
+	if len(dir) < 12 {
+		return nil, errors.New("not a TrueType or OpenType font")
+	}
+	switch string(dir[:4]) {
+	case "\x00\x01\x00\x00", "OTTO", "true":
+	default:
+		return nil, errors.New("not a TrueType or OpenType font")
+	}
+	numTables := int(binary.BigEndian.Uint16(dir[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(dir) {
+			return nil, errors.New("truncated table directory")
+		}
+		start := int64(binary.BigEndian.Uint32(dir[rec+8:]))
+		end := start + int64(binary.BigEndian.Uint32(dir[rec+12:]))
+		if end > int64(len(data)) {
+			return nil, errors.Errorf("truncated %s table", dir[rec:rec+4])
+		}
+		tables[string(dir[rec:rec+4])] = data[start:end]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap"} {
+		if tables[tag] == nil {
+			return nil, errors.Errorf("missing %s table", tag)
+		}
+	}
+
+	f := &FontMetrics{}
+	if len(tables["head"]) < 20 || len(tables["hhea"]) < 36 {
+		return nil, errors.New("truncated font header")
+	}
+	f.unitsPerEm = float64(binary.BigEndian.Uint16(tables["head"][18:]))
+	if f.unitsPerEm == 0 {
+		return nil, errors.New("invalid units per em")
+	}
+	n := int(binary.BigEndian.Uint16(tables["hhea"][34:]))
+	hmtx := tables["hmtx"]
+	if n == 0 || len(hmtx) < 4*n {
+		return nil, errors.New("truncated hmtx table")
+	}
+	f.advances = make([]uint16, n)
+	for i := range f.advances {
+		f.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+	}
+
+	glyph, err := parseCmap(tables["cmap"])
+	if err != nil {
+		return nil, err
+	}
+	f.glyph = glyph
+	return f, nil
+}
+
+// parseCmap returns a lookup of glyphs from the best unicode
+// subtable of the cmap table.
+func parseCmap(cmap []byte) (func(r rune) uint16, error) {
+	if len(cmap) < 4 {
+		return nil, errors.New("truncated cmap table")
+	}
+	var best func(r rune) uint16
+	bestFull := false
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables; i++ {
+		rec := 4 + 8*i
+		if rec+8 > len(cmap) {
+			return nil, errors.New("truncated cmap table")
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		offset := binary.BigEndian.Uint32(cmap[rec+4:])
+		if platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+		if int64(offset)+4 > int64(len(cmap)) {
+			continue
+		}
+		sub := cmap[offset:]
+		switch binary.BigEndian.Uint16(sub) {
+		case 4:
+			if best == nil {
+				best = cmapFormat4(sub)
+			}
+		case 12:
+			// format 12 covers characters outside the basic multilingual plane
+			if !bestFull {
+				best, bestFull = cmapFormat12(sub), true
+			}
+		}
+	}
+	if best == nil {
+		return nil, errors.New("no unicode cmap subtable")
+	}
+	return best, nil
+}
+
+// cmapFormat4 returns a lookup of glyphs from a segment mapping subtable.
+func cmapFormat4(sub []byte) func(r rune) uint16 {
+	u16 := func(i int) uint16 {
+		if i < 0 || i+2 > len(sub) {
+			return 0
+		}
+		return binary.BigEndian.Uint16(sub[i:])
+	}
+	segs := int(u16(6)) / 2
+	ends, starts, deltas, ranges := 14, 16+2*segs, 16+4*segs, 16+6*segs
+	return func(r rune) uint16 {
+		if r < 0 || r > 0xffff {
+			return 0
+		}
+		c := uint16(r)
+		for i := 0; i < segs; i++ {
+			if c > u16(ends+2*i) {
+				continue
+			}
+			if c < u16(starts+2*i) {
+				return 0
+			}
+			delta := u16(deltas + 2*i)
+			rangeOffset := int(u16(ranges + 2*i))
+			if rangeOffset == 0 {
+				return c + delta
+			}
+			g := u16(ranges + 2*i + rangeOffset + 2*int(c-u16(starts+2*i)))
+			if g == 0 {
+				return 0
+			}
+			return g + delta
+		}
+		return 0
+	}
+}
+
+// cmapFormat12 returns a lookup of glyphs from a segmented coverage subtable.
+func cmapFormat12(sub []byte) func(r rune) uint16 {
+	u32 := func(i int) uint32 {
+		if i < 0 || i+4 > len(sub) {
+			return 0
+		}
+		return binary.BigEndian.Uint32(sub[i:])
+	}
+	groups := int(u32(12))
+	return func(r rune) uint16 {
+		c := uint32(r)
+		for i := 0; i < groups; i++ {
+			group := 16 + 12*i
+			if group+12 > len(sub) {
+				return 0
+			}
+			if start, end := u32(group), u32(group+4); c >= start && c <= end {
+				return uint16(u32(group+8) + c - start)
+			}
+		}
+		return 0
+	}
+}
@@ -0,0 +1,263 @@
+package i3bar
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fontTable is a table of a fixture font.
+type fontTable struct {
+	tag  string
+	data []byte
+}
+
+// be appends the big endian encoding of the uint16 and uint32 values to b.
+func be(b []byte, values ...interface{}) []byte {
+	for _, v := range values {
+		switch v := v.(type) {
+		case uint16:
+			b = binary.BigEndian.AppendUint16(b, v)
+		case int:
+			b = binary.BigEndian.AppendUint16(b, uint16(v))
+		case uint32:
+			b = binary.BigEndian.AppendUint32(b, v)
+		}
+	}
+	return b
+}
+
+// buildFont returns a font with tables, whose table offsets
+// are relative to base, e.g. for fonts within a collection.
+func buildFont(base int, tables ...fontTable) []byte {
+	font := be([]byte("\x00\x01\x00\x00"), len(tables), 0, 0, 0)
+	offset := base + 12 + 16*len(tables)
+	var data []byte
+	for _, t := range tables {
+		font = append(font, t.tag...)
+		font = be(font, uint32(0), uint32(offset+len(data)), uint32(len(t.data)))
+		data = append(data, t.data...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+	return append(font, data...)
+}
+
+// buildCollection returns a font collection containing font.
+func buildCollection(font func(base int) []byte) []byte {
+	return append(be([]byte("ttcf"), uint32(0x00010000), uint32(1), uint32(16)), font(16)...)
+}
+
+// fixture tables of a font with 1000 units per em, which maps "abc"
+// to glyphs 1-3, "01" to glyphs 4-5 and U+1F50B to glyph 6.
+func headTable(unitsPerEm int) fontTable {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], uint16(unitsPerEm))
+	return fontTable{"head", head}
+}
+
+func hheaTable(metrics int) fontTable {
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], uint16(metrics))
+	return fontTable{"hhea", hhea}
+}
+
+func hmtxTable(advances ...int) fontTable {
+	var hmtx []byte
+	for _, a := range advances {
+		hmtx = be(hmtx, a, 0)
+	}
+	return fontTable{"hmtx", hmtx}
+}
+
+// cmapFormat4Table maps a-c by delta and 0-1 by the glyph id array.
+func cmapFormat4Table() []byte {
+	segs := 3
+	sub := be(nil, 4, 0, 0, 2*segs, 0, 0, 0)
+	sub = be(sub, int('1'), int('c'), 0xffff, 0)
+	sub = be(sub, int('0'), int('a'), 0xffff)
+	sub = be(sub, 0, 1-int('a'), 1)
+	// the range offset points from its own position to the glyph id array
+	sub = be(sub, 2*segs, 0, 0)
+	sub = be(sub, 4, 5)
+	binary.BigEndian.PutUint16(sub[2:], uint16(len(sub)))
+	return sub
+}
+
+func cmapFormat12Table() []byte {
+	groups := [][3]uint32{{'0', '1', 4}, {'a', 'c', 1}, {0x1f50b, 0x1f50b, 6}}
+	sub := be(nil, 12, 0, uint32(16+12*len(groups)), uint32(0), uint32(len(groups)))
+	for _, g := range groups {
+		sub = be(sub, g[0], g[1], g[2])
+	}
+	return sub
+}
+
+// cmapTable returns a cmap with subtables for the platform and encoding
+// pairs in ids.
+func cmapTable(ids [][2]int, subs ...[]byte) fontTable {
+	cmap := be(nil, 0, len(subs))
+	offset := 4 + 8*len(subs)
+	for i, sub := range subs {
+		cmap = be(cmap, ids[i][0], ids[i][1], uint32(offset))
+		offset += len(sub)
+	}
+	for _, sub := range subs {
+		cmap = append(cmap, sub...)
+	}
+	return fontTable{"cmap", cmap}
+}
+
+// fixtureTables returns the tables of the fixture font with cmap.
+func fixtureTables(cmap fontTable) []fontTable {
+	return []fontTable{
+		headTable(1000),
+		hheaTable(6),
+		hmtxTable(500, 600, 700, 800, 550, 1000),
+		cmap,
+	}
+}
+
+var bmpCmap = cmapTable([][2]int{{3, 1}}, cmapFormat4Table())
+
+var fullCmap = cmapTable([][2]int{{3, 1}, {3, 10}}, cmapFormat4Table(), cmapFormat12Table())
+
+func TestParseFont(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		// widths in pixels of texts at 10 pixels
+		widths map[string]int
+	}{
+		{
+			name: "format 4",
+			data: buildFont(0, fixtureTables(bmpCmap)...),
+			// glyphs outside of the cmap use the advance of glyph 0
+			widths: map[string]int{"abc": 21, "01": 16, "x": 5, "🔋": 5, "": 0},
+		},
+		{
+			name: "format 12",
+			data: buildFont(0, fixtureTables(fullCmap)...),
+			// glyph 6 has no metrics and shares the advance of the last one
+			widths: map[string]int{"abc": 21, "01": 16, "x": 5, "🔋": 10},
+		},
+		{
+			name:   "unicode platform",
+			data:   buildFont(0, fixtureTables(cmapTable([][2]int{{1, 0}, {0, 3}}, cmapFormat12Table(), cmapFormat4Table()))...),
+			widths: map[string]int{"abc": 21, "🔋": 5},
+		},
+		{
+			name: "collection",
+			data: buildCollection(func(base int) []byte {
+				return buildFont(base, fixtureTables(fullCmap)...)
+			}),
+			widths: map[string]int{"abc": 21, "🔋": 10},
+		},
+		{
+			name: "malformed format 4",
+			// more segments than the subtable contains
+			data:   buildFont(0, fixtureTables(cmapTable([][2]int{{3, 1}}, be(nil, 4, 14, 0, 0xfffe, 0, 0, 0)))...),
+			widths: map[string]int{"abc": 15},
+		},
+		{
+			name: "malformed format 12",
+			// more groups than the subtable contains
+			data:   buildFont(0, fixtureTables(cmapTable([][2]int{{3, 10}}, be(nil, 12, 0, uint32(16), uint32(0), uint32(1000))))...),
+			widths: map[string]int{"abc": 15, "🔋": 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseFont(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Size = 10
+			for text, want := range tt.widths {
+				if got := f.Width(text); got != want {
+					t.Errorf("Width(%q) = %d, want %d", text, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFontInvalid(t *testing.T) {
+	valid := buildFont(0, fixtureTables(bmpCmap)...)
+	without := func(tag string) []byte {
+		var tables []fontTable
+		for _, table := range fixtureTables(bmpCmap) {
+			if table.tag != tag {
+				tables = append(tables, table)
+			}
+		}
+		return buildFont(0, tables...)
+	}
+	with := func(replace fontTable) []byte {
+		tables := fixtureTables(bmpCmap)
+		for i := range tables {
+			if tables[i].tag == replace.tag {
+				tables[i] = replace
+			}
+		}
+		return buildFont(0, tables...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{"empty", nil, "not a TrueType or OpenType font"},
+		{"unknown version", append([]byte("wOFF"), valid[4:]...), "not a TrueType or OpenType font"},
+		{"truncated directory", be([]byte("\x00\x01\x00\x00"), 1, 0, 0, 0), "truncated table directory"},
+		{"truncated table", valid[:len(valid)-8], "truncated cmap table"},
+		{"missing head", without("head"), "missing head table"},
+		{"missing hmtx", without("hmtx"), "missing hmtx table"},
+		{"missing cmap", without("cmap"), "missing cmap table"},
+		{"truncated head", with(fontTable{"head", make([]byte, 18)}), "truncated font header"},
+		{"truncated hhea", with(fontTable{"hhea", make([]byte, 34)}), "truncated font header"},
+		{"zero units per em", with(headTable(0)), "invalid units per em"},
+		{"no metrics", with(hheaTable(0)), "truncated hmtx table"},
+		{"truncated hmtx", with(hheaTable(7)), "truncated hmtx table"},
+		{"truncated cmap", with(fontTable{"cmap", []byte{0, 0}}), "truncated cmap table"},
+		{"truncated cmap records", with(fontTable{"cmap", be(nil, 0, 2, 3, 1, uint32(20))}), "truncated cmap table"},
+		{"no unicode cmap", with(cmapTable([][2]int{{1, 0}}, cmapFormat4Table())), "no unicode cmap subtable"},
+		{"cmap offset out of range", with(fontTable{"cmap", be(nil, 0, 1, 3, 1, uint32(1000))}), "no unicode cmap subtable"},
+		{"unsupported cmap format", with(cmapTable([][2]int{{3, 1}}, be(nil, 6, 10, 0, 0, 0))), "no unicode cmap subtable"},
+		{"truncated collection", []byte("ttcf"), "not a TrueType or OpenType font"},
+		{"collection offset out of range", be([]byte("ttcf"), uint32(0x00010000), uint32(1), uint32(1000)), "invalid font collection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFont(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadFont(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.ttf")
+	if err := os.WriteFile(path, buildFont(0, fixtureTables(bmpCmap)...), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadFont(path, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Width("abc"); got != 42 {
+		t.Errorf("Width(abc) = %d, want 42", got)
+	}
+	if got := f.MinWidth("abc"); got.Pixels != 42 {
+		t.Errorf("MinWidth(abc) = %+v, want 42 pixels", got)
+	}
+
+	if _, err := LoadFont(filepath.Join(t.TempDir(), "missing.ttf"), 20); err == nil {
+		t.Error("loading missing font succeeded")
+	}
+}
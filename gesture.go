@@ -0,0 +1,113 @@
+package i3bar
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Gesture is a combination of a button and the modifiers held down,
+// written as text like "left", "shift+right" or "ctrl+alt+scroll_up".
+type Gesture struct {
+	Button    Button
+	Modifiers Modifiers
+}
+
+// modifierAliases are common names of modifiers besides the ones used by i3bar.
+var modifierAliases = map[string]Modifier{
+	"ctrl":  Control,
+	"alt":   Mod1,
+	"super": Mod4,
+}
+
+// ParseGesture parses the text form of a Gesture.
+func ParseGesture(s string) (Gesture, error) {
+	var g Gesture
+	err := g.UnmarshalText([]byte(s))
+	return g, err
+}
+
+// GestureOf returns the Gesture of a click event.
+// Lock and Mod2 (usually num lock) are ignored.
+func GestureOf(ev ClickEvent) Gesture {
+	return Gesture{Button: ev.Button, Modifiers: ev.Modifiers &^ ignoredModifiers}
+}
+
+// UnmarshalText decodes a Gesture from text, modifiers and the
+// button separated by +. Names are case insensitive.
+func (g *Gesture) UnmarshalText(t []byte) error {
+	parts := strings.Split(string(t), "+")
+	var button Button
+	if err := button.UnmarshalText([]byte(strings.TrimSpace(parts[len(parts)-1]))); err != nil {
+		return errors.Wrapf(err, "invalid gesture %s", string(t))
+	}
+
+	var modifiers Modifiers
+	for _, part := range parts[:len(parts)-1] {
+		name := strings.ToLower(strings.TrimSpace(part))
+		m, ok := modifierAliases[name]
+		if !ok {
+			if err := m.UnmarshalText([]byte(name)); err != nil {
+				return errors.Wrapf(err, "invalid gesture %s", string(t))
+			}
+		}
+		modifiers |= Modifiers(m)
+	}
+
+	*g = Gesture{Button: button, Modifiers: modifiers &^ ignoredModifiers}
+	return nil
+}
+
+// MarshalText encodes the Gesture into its text form.
+func (g Gesture) MarshalText() ([]byte, error) {
+	button, err := g.Button.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	var parts []string
+	for _, m := range g.Modifiers.List() {
+		name, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, strings.ToLower(string(name)))
+	}
+	return []byte(strings.Join(append(parts, string(button)), "+")), nil
+}
+
+// String returns the text form of the Gesture.
+func (g Gesture) String() string {
+	t, err := g.MarshalText()
+	if err != nil {
+		return g.Button.String()
+	}
+	return string(t)
+}
+
+// GestureMap maps gestures to the names of actions, e.g. "shift+left"
+// to "open". It can be decoded from a json config file.
+type GestureMap map[Gesture]string
+
+// Action returns the name of the action mapped to the gesture of ev.
+func (m GestureMap) Action(ev ClickEvent) (string, bool) {
+	action, ok := m[GestureOf(ev)]
+	return action, ok
+}
+
+// Actions maps the names of the actions a block supports to their handlers.
+type Actions map[string]func(ev ClickEvent)
+
+// Handler returns a click handler performing the action of actions
+// mapped to the gesture of every click event.
+// Click events without mapped or supported action are ignored.
+func (m GestureMap) Handler(actions Actions) func(ev ClickEvent) {
+	return func(ev ClickEvent) {
+		action, ok := m.Action(ev)
+		if !ok {
+			return
+		}
+		if fn := actions[action]; fn != nil {
+			fn(ev)
+		}
+	}
+}
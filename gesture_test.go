@@ -0,0 +1,81 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseGesture(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Gesture
+		text string
+		err  bool
+	}{
+		{in: "left", want: Gesture{Button: LeftClick}, text: "left"},
+		{in: "Shift+Right", want: Gesture{Button: RightClick, Modifiers: Modifiers(Shift)}, text: "shift+right"},
+		{in: "ctrl + alt + scroll_up", want: Gesture{Button: ScrollUp, Modifiers: Modifiers(Control | Mod1)}, text: "control+mod1+scroll_up"},
+		{in: "super+mod5+back", want: Gesture{Button: Back, Modifiers: Modifiers(Mod4 | Mod5)}, text: "mod4+mod5+back"},
+		// lock and num lock never change the gesture
+		{in: "lock+mod2+middle", want: Gesture{Button: MiddleClick}, text: "middle"},
+		{in: "", err: true},
+		{in: "shift", err: true},
+		{in: "hyper+left", err: true},
+		{in: "left+right", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseGesture(tt.in)
+			if tt.err {
+				if err == nil {
+					t.Errorf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if s := got.String(); s != tt.text {
+				t.Errorf("String() = %q, want %q", s, tt.text)
+			}
+		})
+	}
+}
+
+func TestGestureMap(t *testing.T) {
+	var m GestureMap
+	if err := json.Unmarshal([]byte(`{"left": "toggle", "shift+left": "open", "right": "missing"}`), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	handler := m.Handler(Actions{
+		"toggle": func(ClickEvent) { got = append(got, "toggle") },
+		"open":   func(ClickEvent) { got = append(got, "open") },
+	})
+
+	tests := []struct {
+		name string
+		ev   ClickEvent
+		want string
+	}{
+		{"plain", ClickEvent{Button: LeftClick}, "toggle"},
+		{"modifier", ClickEvent{Button: LeftClick, Modifiers: Modifiers(Shift)}, "open"},
+		{"ignored modifiers", ClickEvent{Button: LeftClick, Modifiers: Modifiers(Shift | Mod2)}, "open"},
+		{"unsupported action", ClickEvent{Button: RightClick}, ""},
+		{"unmapped", ClickEvent{Button: MiddleClick}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			handler(tt.ev)
+			if performed := strings.Join(got, ","); performed != tt.want {
+				t.Errorf("performed %q, want %q", performed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/g0dsCookie/go-i3bar
+
+go 1.21
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/text v0.14.0
+)
@@ -0,0 +1,96 @@
+package i3bar
+
+import (
+	"math"
+	"sort"
+)
+
+// GradientStop is a color at a position from 0.0 to 1.0 of a ColorGradient.
+type GradientStop struct {
+	Pos   float64
+	Color Color
+}
+
+// ColorGradient interpolates colors between stops, so that numeric
+// blocks can be shaded smoothly, e.g. from green through yellow to red.
+type ColorGradient struct {
+	stops []GradientStop
+}
+
+// Gradient creates a ColorGradient from one color to another.
+func Gradient(from, to Color) ColorGradient {
+	return MultiGradient(from, to)
+}
+
+// MultiGradient creates a ColorGradient through all colors
+// with evenly distributed stops.
+func MultiGradient(colors ...Color) ColorGradient {
+	stops := make([]GradientStop, len(colors))
+	for i, c := range colors {
+		var pos float64
+		if len(colors) > 1 {
+			pos = float64(i) / float64(len(colors)-1)
+		}
+		stops[i] = GradientStop{Pos: pos, Color: c}
+	}
+	return GradientStops(stops...)
+}
+
+// GradientStops creates a ColorGradient from stops at arbitrary positions.
+func GradientStops(stops ...GradientStop) ColorGradient {
+	sorted := append([]GradientStop(nil), stops...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Pos < sorted[j].Pos
+	})
+	return ColorGradient{stops: sorted}
+}
+
+// At returns the color at position t from 0.0 to 1.0.
+// Positions before the first or after the last stop get the color of that stop.
+// Invalid colors are not interpolated, the nearer stop is returned instead.
+func (g ColorGradient) At(t float64) Color {
+	if len(g.stops) == 0 {
+		return ""
+	}
+	first, last := g.stops[0], g.stops[len(g.stops)-1]
+	if t <= first.Pos || math.IsNaN(t) {
+		return first.Color
+	}
+	if t >= last.Pos {
+		return last.Color
+	}
+
+	i := sort.Search(len(g.stops), func(i int) bool {
+		return g.stops[i].Pos >= t
+	})
+	from, to := g.stops[i-1], g.stops[i]
+	return mixColors(from.Color, to.Color, (t-from.Pos)/(to.Pos-from.Pos))
+}
+
+// AtValue returns the color for v scaled from min to max.
+func (g ColorGradient) AtValue(v, min, max float64) Color {
+	if max <= min {
+		return g.At(0)
+	}
+	return g.At((v - min) / (max - min))
+}
+
+// mixColors interpolates linearly between the colors a and b.
+func mixColors(a, b Color, t float64) Color {
+	ar, ag, ab, aa, errA := a.RGBA()
+	br, bg, bb, ba, errB := b.RGBA()
+	if errA != nil || errB != nil {
+		if t < 0.5 {
+			return a
+		}
+		return b
+	}
+
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t))
+	}
+	if a.HasAlpha() || b.HasAlpha() {
+		return RGBA(mix(ar, br), mix(ag, bg), mix(ab, bb), mix(aa, ba))
+	}
+	return RGB(mix(ar, br), mix(ag, bg), mix(ab, bb))
+}
@@ -0,0 +1,60 @@
+package i3bar
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColorGradient(t *testing.T) {
+	traffic := MultiGradient("#00ff00", "#ffff00", "#ff0000")
+
+	tests := []struct {
+		name     string
+		gradient ColorGradient
+		t        float64
+		want     Color
+	}{
+		{"start", Gradient("#000000", "#ffffff"), 0, "#000000"},
+		{"end", Gradient("#000000", "#ffffff"), 1, "#ffffff"},
+		{"middle", Gradient("#000000", "#ffffff"), 0.5, "#808080"},
+		{"before first stop", Gradient("#000000", "#ffffff"), -1, "#000000"},
+		{"after last stop", Gradient("#000000", "#ffffff"), 2, "#ffffff"},
+		{"nan", Gradient("#000000", "#ffffff"), math.NaN(), "#000000"},
+		{"multiple stops", traffic, 0.5, "#ffff00"},
+		{"between stops", traffic, 0.75, "#ff8000"},
+		{"alpha", Gradient("#00000000", "#ffffffff"), 0.5, "#80808080"},
+		{"invalid color near", Gradient("invalid", "#ffffff"), 0.25, "invalid"},
+		{"invalid color far", Gradient("invalid", "#ffffff"), 0.75, "#ffffff"},
+		{"single color", MultiGradient("#ff0000"), 0.5, "#ff0000"},
+		{"no colors", MultiGradient(), 0.5, ""},
+		// stops are sorted by their position
+		{"unsorted stops", GradientStops(GradientStop{Pos: 1, Color: "#ffffff"}, GradientStop{Pos: 0.5, Color: "#000000"}), 0.75, "#808080"},
+		{"before unsorted stops", GradientStops(GradientStop{Pos: 1, Color: "#ffffff"}, GradientStop{Pos: 0.5, Color: "#000000"}), 0.25, "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.gradient.At(tt.t); got != tt.want {
+				t.Errorf("At(%v) = %q, want %q", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColorGradientAtValue(t *testing.T) {
+	g := Gradient("#000000", "#ffffff")
+	tests := []struct {
+		v, min, max float64
+		want        Color
+	}{
+		{50, 0, 100, "#808080"},
+		{20, 20, 80, "#000000"},
+		{100, 0, 100, "#ffffff"},
+		// empty ranges use the first color
+		{50, 50, 50, "#000000"},
+	}
+	for _, tt := range tests {
+		if got := g.AtValue(tt.v, tt.min, tt.max); got != tt.want {
+			t.Errorf("AtValue(%v, %v, %v) = %q, want %q", tt.v, tt.min, tt.max, got, tt.want)
+		}
+	}
+}
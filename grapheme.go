@@ -0,0 +1,102 @@
+package i3bar
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultEllipsis is appended by Truncate to shortened texts.
+const DefaultEllipsis = "…"
+
+// zeroWidthJoiner joins emoji into a single grapheme.
+const zeroWidthJoiner = '\u200d'
+
+// Graphemes splits s into user-perceived characters. Combining marks,
+// variation selectors, emoji modifiers and tags stay with their base
+// character, emoji joined by zero width joiners, flags made of regional
+// indicator pairs and CRLF form a single grapheme.
+// This approximates extended grapheme clusters as defined by Unicode
+// without Hangul syllable and Indic conjunct rules.
+func Graphemes(s string) []string {
+	var graphemes []string
+	start := 0
+	var prev rune
+	regional := 0
+	for i, r := range s {
+		if i > 0 && !extendsGrapheme(prev, r, regional) {
+			graphemes = append(graphemes, s[start:i])
+			start = i
+			regional = 0
+		}
+		if isRegionalIndicator(r) {
+			regional++
+		}
+		prev = r
+	}
+	if start < len(s) {
+		graphemes = append(graphemes, s[start:])
+	}
+	return graphemes
+}
+
+// GraphemeCount returns the number of graphemes in s, see Graphemes.
+func GraphemeCount(s string) int {
+	return len(Graphemes(s))
+}
+
+// Truncate shortens s to at most n graphemes including DefaultEllipsis.
+func Truncate(s string, n int) string {
+	return TruncateWith(s, n, DefaultEllipsis)
+}
+
+// TruncateWith shortens s to at most n graphemes including ellipsis,
+// which is appended if s got shortened and itself shortened to n
+// graphemes if it is longer. Graphemes are never split,
+// so combining sequences and emoji stay intact.
+func TruncateWith(s string, n int, ellipsis string) string {
+	if n <= 0 {
+		return ""
+	}
+	graphemes := Graphemes(s)
+	if len(graphemes) <= n {
+		return s
+	}
+	dots := Graphemes(ellipsis)
+	if len(dots) > n {
+		// the ellipsis alone exceeds n, so it is shortened instead
+		return strings.Join(dots[:n], "")
+	}
+	return strings.Join(graphemes[:n-len(dots)], "") + ellipsis
+}
+
+// extendsGrapheme reports whether r continues the grapheme ending with prev.
+// regional is the number of regional indicators in the current grapheme.
+func extendsGrapheme(prev, r rune, regional int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner:
+		return true
+	case r == zeroWidthJoiner:
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= 0xfe00 && r <= 0xfe0f, r >= 0xe0100 && r <= 0xe01ef:
+		// variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		// emoji tag sequences
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return regional%2 == 1
+	}
+	return false
+}
+
+// isRegionalIndicator reports whether r is a regional indicator used in flags.
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,37 @@
+package i3bar
+
+import "testing"
+
+func TestTruncateWith(t *testing.T) {
+	tests := []struct {
+		s        string
+		n        int
+		ellipsis string
+		want     string
+	}{
+		{"hello", 10, "...", "hello"},
+		{"hello", 5, "...", "hello"},
+		{"hello world", 8, "...", "hello..."},
+		{"hello world", 4, "…", "hel…"},
+		{"hello world", 4, "", "hell"},
+		// the ellipsis is shortened if it does not fit
+		{"hello world", 3, "...", "..."},
+		{"hello world", 2, "...", ".."},
+		{"hello world", 1, "...", "."},
+		{"hello world", 0, "...", ""},
+		{"hello world", -1, "...", ""},
+		// graphemes are never split
+		{"éééé", 3, "…", "éé…"},
+		{"👍🏽👍🏽👍🏽", 2, "…", "👍🏽…"},
+		{"abc", 1, "👍🏽👍🏽", "👍🏽"},
+	}
+	for _, tt := range tests {
+		got := TruncateWith(tt.s, tt.n, tt.ellipsis)
+		if got != tt.want {
+			t.Errorf("TruncateWith(%q, %d, %q) = %q, want %q", tt.s, tt.n, tt.ellipsis, got, tt.want)
+		}
+		if tt.n >= 0 && GraphemeCount(got) > tt.n {
+			t.Errorf("TruncateWith(%q, %d, %q) returned %d graphemes", tt.s, tt.n, tt.ellipsis, GraphemeCount(got))
+		}
+	}
+}
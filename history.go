@@ -0,0 +1,137 @@
+package i3bar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// barGraphLevels are the block elements used by BarGraph from low to high.
+var barGraphLevels = []rune("▁▂▃▄▅▆▇█")
+
+// BarGraph renders samples as a graph of vertical bars with one
+// sample per character, scaled from 0 to the largest sample.
+func BarGraph(samples []float64) string {
+	max := 0.0
+	for _, v := range samples {
+		max = math.Max(max, v)
+	}
+	return BarGraphRange(samples, 0, max)
+}
+
+// BarGraphRange renders samples as a graph of vertical bars like BarGraph,
+// but scaled from min to max. Samples outside are clamped.
+func BarGraphRange(samples []float64, min, max float64) string {
+	var sb strings.Builder
+	top := float64(len(barGraphLevels) - 1)
+	for _, v := range samples {
+		level := 0
+		if max > min && !math.IsNaN(v) {
+			level = int(math.Round(math.Max(0, math.Min(1, (v-min)/(max-min))) * top))
+		}
+		sb.WriteRune(barGraphLevels[level])
+	}
+	return sb.String()
+}
+
+// History keeps the latest samples of a value, e.g. the CPU load,
+// in a ring buffer of fixed size to render its recent trend.
+type History struct {
+	mux     sync.Mutex
+	samples []float64
+	next    int
+	full    bool
+}
+
+// NewHistory creates a History keeping the latest size samples.
+func NewHistory(size int) *History {
+	if size < 1 {
+		size = 1
+	}
+	return &History{samples: make([]float64, size)}
+}
+
+// Add appends a sample, dropping the oldest one if the History is full.
+func (h *History) Add(v float64) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.samples[h.next] = v
+	h.next = (h.next + 1) % len(h.samples)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Len returns the number of samples kept.
+func (h *History) Len() int {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if h.full {
+		return len(h.samples)
+	}
+	return h.next
+}
+
+// Samples returns a copy of the samples kept from the oldest to the latest.
+func (h *History) Samples() []float64 {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if !h.full {
+		return append([]float64(nil), h.samples[:h.next]...)
+	}
+	samples := make([]float64, 0, len(h.samples))
+	samples = append(samples, h.samples[h.next:]...)
+	return append(samples, h.samples[:h.next]...)
+}
+
+// Reset drops all samples.
+func (h *History) Reset() {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	h.next, h.full = 0, false
+}
+
+// Stats returns the smallest, average and largest sample.
+// ok is false if there are no samples.
+func (h *History) Stats() (min, avg, max float64, ok bool) {
+	samples := h.Samples()
+	if len(samples) == 0 {
+		return 0, 0, 0, false
+	}
+	min, max = math.Inf(1), math.Inf(-1)
+	sum := 0.0
+	for _, v := range samples {
+		min = math.Min(min, v)
+		max = math.Max(max, v)
+		sum += v
+	}
+	return min, sum / float64(len(samples)), max, true
+}
+
+// Sparkline renders the samples as a braille sparkline, see Sparkline.
+func (h *History) Sparkline() string {
+	return Sparkline(h.Samples())
+}
+
+// BarGraph renders the samples as a graph of vertical bars, see BarGraph.
+func (h *History) BarGraph() string {
+	return BarGraph(h.Samples())
+}
+
+// Summary renders the smallest, average and largest sample as
+// "min/avg/max" using format, e.g. a closure calling HumanBytes.
+// The samples are formatted with one decimal if format is nil.
+// An empty string is returned if there are no samples.
+func (h *History) Summary(format func(float64) string) string {
+	min, avg, max, ok := h.Stats()
+	if !ok {
+		return ""
+	}
+	if format == nil {
+		format = func(v float64) string {
+			return fmt.Sprintf("%.1f", v)
+		}
+	}
+	return format(min) + "/" + format(avg) + "/" + format(max)
+}
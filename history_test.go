@@ -0,0 +1,97 @@
+package i3bar
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		add     []float64
+		samples []float64
+		summary string
+	}{
+		{"empty", 3, nil, []float64{}, ""},
+		{"partial", 3, []float64{1, 2}, []float64{1, 2}, "1.0/1.5/2.0"},
+		{"full", 3, []float64{1, 2, 3}, []float64{1, 2, 3}, "1.0/2.0/3.0"},
+		// the oldest samples are dropped
+		{"wrapped", 3, []float64{1, 2, 3, 4, 5}, []float64{3, 4, 5}, "3.0/4.0/5.0"},
+		{"minimum size", 0, []float64{1, 2}, []float64{2}, "2.0/2.0/2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistory(tt.size)
+			for _, v := range tt.add {
+				h.Add(v)
+			}
+			samples := h.Samples()
+			if len(samples) == 0 {
+				samples = []float64{}
+			}
+			if !reflect.DeepEqual(samples, tt.samples) {
+				t.Errorf("Samples() = %v, want %v", samples, tt.samples)
+			}
+			if h.Len() != len(tt.samples) {
+				t.Errorf("Len() = %d, want %d", h.Len(), len(tt.samples))
+			}
+			if got := h.Summary(nil); got != tt.summary {
+				t.Errorf("Summary() = %q, want %q", got, tt.summary)
+			}
+		})
+	}
+}
+
+func TestHistoryRender(t *testing.T) {
+	h := NewHistory(4)
+	for _, v := range []float64{0, 2, 4, 8} {
+		h.Add(v)
+	}
+	if got := h.BarGraph(); got != "▁▃▅█" {
+		t.Errorf("BarGraph() = %q", got)
+	}
+	if got, want := h.Sparkline(), Sparkline([]float64{0, 2, 4, 8}); got != want {
+		t.Errorf("Sparkline() = %q, want %q", got, want)
+	}
+	format := func(v float64) string { return strconv.Itoa(int(v)) + "%" }
+	if got := h.Summary(format); got != "0%/3%/8%" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// the returned samples are a copy
+	h.Samples()[0] = 100
+	h.Reset()
+	if h.Len() != 0 || h.BarGraph() != "" {
+		t.Error("Reset kept samples")
+	}
+	h.Add(1)
+	if got := h.Samples(); !reflect.DeepEqual(got, []float64{1}) {
+		t.Errorf("Samples() = %v after Reset", got)
+	}
+}
+
+func TestBarGraphRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []float64
+		min, max float64
+		want     string
+	}{
+		{"levels", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 0, 7, "▁▂▃▄▅▆▇█"},
+		{"clamped", []float64{-5, 50}, 0, 10, "▁█"},
+		{"offset range", []float64{20, 50, 80}, 20, 80, "▁▅█"},
+		{"empty range", []float64{5, 5}, 5, 5, "▁▁"},
+		{"nan", []float64{math.NaN()}, 0, 1, "▁"},
+		{"no samples", nil, 0, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BarGraphRange(tt.samples, tt.min, tt.max); got != tt.want {
+				t.Errorf("BarGraphRange(%v, %v, %v) = %q, want %q", tt.samples, tt.min, tt.max, got, tt.want)
+			}
+		})
+	}
+}
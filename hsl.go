@@ -0,0 +1,160 @@
+package i3bar
+
+import "math"
+
+// HSL creates an opaque Color from its hue in degrees and its saturation
+// and lightness from 0 to 1. Values outside are wrapped or clamped.
+func HSL(h, s, l float64) Color {
+	h, s, l = normalizeHue(h), clamp01(s), clamp01(l)
+	c := (1 - math.Abs(2*l-1)) * s
+	return hueToColor(h, c, l-c/2)
+}
+
+// HSV creates an opaque Color from its hue in degrees and its saturation
+// and value from 0 to 1. Values outside are wrapped or clamped.
+func HSV(h, s, v float64) Color {
+	h, s, v = normalizeHue(h), clamp01(s), clamp01(v)
+	c := v * s
+	return hueToColor(h, c, v-c)
+}
+
+// HSL returns the hue in degrees and the saturation and lightness
+// from 0 to 1 of the Color.
+func (c Color) HSL() (h, s, l float64, err error) {
+	r, g, b, err := c.RGB()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	h, min, max := hue(r, g, b)
+	l = (max + min) / 2
+	if max != min {
+		s = (max - min) / (1 - math.Abs(2*l-1))
+	}
+	return h, s, l, nil
+}
+
+// HSV returns the hue in degrees and the saturation and value
+// from 0 to 1 of the Color.
+func (c Color) HSV() (h, s, v float64, err error) {
+	r, g, b, err := c.RGB()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	h, min, max := hue(r, g, b)
+	if max > 0 {
+		s = (max - min) / max
+	}
+	return h, s, max, nil
+}
+
+// Lighten returns the Color with its lightness increased by amount.
+// Invalid colors are returned unchanged.
+func (c Color) Lighten(amount float64) Color {
+	return c.adjustHSL(func(h, s, l float64) (float64, float64, float64) {
+		return h, s, l + amount
+	})
+}
+
+// Darken returns the Color with its lightness decreased by amount.
+// Invalid colors are returned unchanged.
+func (c Color) Darken(amount float64) Color {
+	return c.Lighten(-amount)
+}
+
+// Saturate returns the Color with its saturation increased by amount.
+// Invalid colors are returned unchanged.
+func (c Color) Saturate(amount float64) Color {
+	return c.adjustHSL(func(h, s, l float64) (float64, float64, float64) {
+		return h, s + amount, l
+	})
+}
+
+// Desaturate returns the Color with its saturation decreased by amount.
+// Invalid colors are returned unchanged.
+func (c Color) Desaturate(amount float64) Color {
+	return c.Saturate(-amount)
+}
+
+// Rotate returns the Color with its hue rotated by degrees,
+// e.g. 180 for the complementary color.
+// Invalid colors are returned unchanged.
+func (c Color) Rotate(degrees float64) Color {
+	return c.adjustHSL(func(h, s, l float64) (float64, float64, float64) {
+		return h + degrees, s, l
+	})
+}
+
+// adjustHSL returns the Color with its hue, saturation and lightness
+// changed by fn. The alpha channel is kept.
+func (c Color) adjustHSL(fn func(h, s, l float64) (float64, float64, float64)) Color {
+	_, _, _, a, alpha, err := parseHex(string(c))
+	if err != nil {
+		return c
+	}
+	h, s, l, _ := c.HSL()
+	adjusted := HSL(fn(h, s, l))
+	if !alpha {
+		return adjusted
+	}
+	r, g, b, _ := adjusted.RGB()
+	return RGBA(r, g, b, a)
+}
+
+// hue returns the hue in degrees and the smallest
+// and largest component from 0 to 1 of a color.
+func hue(r, g, b uint8) (h, min, max float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	min = math.Min(rf, math.Min(gf, bf))
+	max = math.Max(rf, math.Max(gf, bf))
+	d := max - min
+	switch {
+	case d == 0:
+		h = 0
+	case max == rf:
+		h = math.Mod((gf-bf)/d, 6)
+	case max == gf:
+		h = (bf-rf)/d + 2
+	default:
+		h = (rf-gf)/d + 4
+	}
+	return normalizeHue(h * 60), min, max
+}
+
+// hueToColor creates a Color from its hue in degrees, chroma
+// and the offset m added to all components.
+func hueToColor(h, c, m float64) Color {
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	component := func(v float64) uint8 {
+		return uint8(math.Round(clamp01(v+m) * 255))
+	}
+	return RGB(component(r), component(g), component(b))
+}
+
+// normalizeHue wraps h into [0, 360).
+func normalizeHue(h float64) float64 {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// clamp01 clamps v into [0, 1].
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
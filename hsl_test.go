@@ -0,0 +1,111 @@
+package i3bar
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		name    string
+		h, s, l float64
+		want    Color
+	}{
+		{"red", 0, 1, 0.5, "#ff0000"},
+		{"green", 120, 1, 0.5, "#00ff00"},
+		{"blue", 240, 1, 0.5, "#0000ff"},
+		{"orange", 30, 1, 0.5, "#ff8000"},
+		{"gray", 0, 0, 0.5, "#808080"},
+		{"white", 90, 1, 1, "#ffffff"},
+		{"wrapped hue", 480, 1, 0.5, "#00ff00"},
+		{"negative hue", -120, 1, 0.5, "#0000ff"},
+		{"clamped", 0, 2, -1, "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HSL(tt.h, tt.s, tt.l)
+			if got != tt.want {
+				t.Fatalf("HSL(%v, %v, %v) = %q, want %q", tt.h, tt.s, tt.l, got, tt.want)
+			}
+			// the conversion round trips up to rounding
+			h, s, l, err := got.HSL()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again := HSL(h, s, l); again != got {
+				t.Errorf("round trip through %v, %v, %v got %q", h, s, l, again)
+			}
+		})
+	}
+}
+
+func TestHSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		h, s, v float64
+		want    Color
+	}{
+		{"red", 0, 1, 1, "#ff0000"},
+		{"dark green", 120, 1, 0.5, "#008000"},
+		{"pale blue", 240, 0.5, 1, "#8080ff"},
+		{"black", 300, 1, 0, "#000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HSV(tt.h, tt.s, tt.v)
+			if got != tt.want {
+				t.Fatalf("HSV(%v, %v, %v) = %q, want %q", tt.h, tt.s, tt.v, got, tt.want)
+			}
+			h, s, v, err := got.HSV()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again := HSV(h, s, v); again != got {
+				t.Errorf("round trip through %v, %v, %v got %q", h, s, v, again)
+			}
+		})
+	}
+
+	if _, _, _, err := Color("invalid").HSV(); err == nil {
+		t.Error("HSV of invalid color succeeded")
+	}
+	if _, _, _, err := Color("invalid").HSL(); err == nil {
+		t.Error("HSL of invalid color succeeded")
+	}
+}
+
+func TestColorHSL(t *testing.T) {
+	h, s, l, err := Color("#ff8000").HSL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(h-30) > 0.5 || s != 1 || math.Abs(l-0.5) > 0.01 {
+		t.Errorf("got %v, %v, %v, want 30, 1, 0.5", h, s, l)
+	}
+}
+
+func TestColorAdjust(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Color
+		want Color
+	}{
+		{"lighten", Color("#ff0000").Lighten(0.25), "#ff8080"},
+		{"darken", Color("#ff0000").Darken(0.25), "#800000"},
+		{"lighten clamped", Color("#ff0000").Lighten(1), "#ffffff"},
+		{"desaturate", Color("#ff0000").Desaturate(1), "#808080"},
+		{"saturate", Color("#bf4040").Saturate(0.5), "#ff0000"},
+		{"rotate", Color("#ff0000").Rotate(120), "#00ff00"},
+		{"complementary", Color("#ff0000").Rotate(180), "#00ffff"},
+		// the alpha channel is kept
+		{"alpha", Color("#ff000080").Darken(0.25), "#80000080"},
+		{"invalid", Color("invalid").Lighten(0.5), "invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
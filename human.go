@@ -0,0 +1,105 @@
+package i3bar
+
+import (
+	"math"
+	"strconv"
+)
+
+// Prefixes selects the unit prefixes of humanized values.
+type Prefixes int
+
+const (
+	// Binary prefixes are powers of 1024, e.g. KiB.
+	Binary Prefixes = iota
+	// Decimal prefixes are powers of 1000, e.g. kB.
+	Decimal
+)
+
+var (
+	// binaryPrefixes in ascending order.
+	binaryPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+	// decimalPrefixes in ascending order.
+	decimalPrefixes = []string{"", "k", "M", "G", "T", "P", "E"}
+	// fractionPrefixes in descending order.
+	fractionPrefixes = []string{"", "m", "µ", "n", "p"}
+)
+
+// HumanBytes formats a byte count with precision decimals and
+// binary or decimal prefixes, e.g. "1.4 GiB" or "1.5 GB".
+// Byte counts below the first prefix are formatted without decimals.
+func HumanBytes(n float64, precision int, p Prefixes) string {
+	if p == Decimal {
+		return humanize(n, precision, 1000, decimalPrefixes, "B")
+	}
+	return humanize(n, precision, 1024, binaryPrefixes, "B")
+}
+
+// HumanBits formats a bitrate in bits per second with precision decimals
+// and binary or decimal prefixes, e.g. "12.3 Mbit/s".
+func HumanBits(n float64, precision int, p Prefixes) string {
+	if p == Binary {
+		return humanize(n, precision, 1024, binaryPrefixes, "bit/s")
+	}
+	return humanize(n, precision, 1000, decimalPrefixes, "bit/s")
+}
+
+// HumanSI formats n in unit with precision decimals and SI prefixes,
+// e.g. "1.2 kW" or "350 mV".
+func HumanSI(n float64, precision int, unit string) string {
+	if n != 0 && math.Abs(n) < 1 {
+		i := 0
+		for ; math.Abs(n) < 1 && i < len(fractionPrefixes)-1; i++ {
+			n *= 1000
+		}
+		// rounding may reach the next larger prefix, e.g. 999.99 mV
+		if math.Abs(roundHuman(n, precision)) >= 1000 {
+			n /= 1000
+			i--
+		}
+		if i > 0 {
+			return formatHuman(n, precision, fractionPrefixes[i]+unit)
+		}
+	}
+	return humanize(n, precision, 1000, decimalPrefixes, unit)
+}
+
+// humanize scales n by base until it fits the largest fitting prefix.
+// The prefix is chosen for n rounded to precision decimals, so that
+// e.g. 1023.99 KiB is formatted as "1.0 MiB" instead of "1024.0 KiB".
+func humanize(n float64, precision int, base float64, prefixes []string, unit string) string {
+	i := 0
+	for ; math.Abs(n) >= base && i < len(prefixes)-1; i++ {
+		n /= base
+	}
+	for i < len(prefixes)-1 && math.Abs(roundHuman(n, humanPrecision(n, precision, i))) >= base {
+		n /= base
+		i++
+	}
+	return formatHuman(n, humanPrecision(n, precision, i), prefixes[i]+unit)
+}
+
+// humanPrecision returns the decimals used to format n with the prefix
+// at index i. Whole numbers below the first prefix have no decimals.
+func humanPrecision(n float64, precision, i int) int {
+	if i == 0 && n == math.Trunc(n) {
+		return 0
+	}
+	return precision
+}
+
+// roundHuman rounds n to precision decimals the same way formatHuman does.
+func roundHuman(n float64, precision int) float64 {
+	if precision < 0 {
+		precision = 0
+	}
+	r, _ := strconv.ParseFloat(strconv.FormatFloat(n, 'f', precision, 64), 64)
+	return r
+}
+
+// formatHuman formats n with precision decimals followed by unit.
+func formatHuman(n float64, precision int, unit string) string {
+	if precision < 0 {
+		precision = 0
+	}
+	return strconv.FormatFloat(n, 'f', precision, 64) + " " + unit
+}
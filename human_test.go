@@ -0,0 +1,37 @@
+package i3bar
+
+import "testing"
+
+func TestHuman(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"bytes", HumanBytes(512, 1, Binary), "512 B"},
+		{"binary", HumanBytes(1536, 1, Binary), "1.5 KiB"},
+		{"decimal", HumanBytes(1500, 1, Decimal), "1.5 kB"},
+		{"bytes rounded up to prefix", HumanBytes(1023.99, 1, Binary), "1.0 KiB"},
+		{"rounded up to next prefix", HumanBytes(1048575, 1, Binary), "1.0 MiB"},
+		{"decimal rounded up to next prefix", HumanBytes(999999, 1, Decimal), "1.0 MB"},
+		{"negative rounded up to next prefix", HumanBytes(-1048575, 1, Binary), "-1.0 MiB"},
+		{"largest prefix", HumanBytes(1<<70, 1, Binary), "1024.0 EiB"},
+		{"not rounded up", HumanBytes(1048063, 1, Binary), "1023.5 KiB"},
+		{"bits", HumanBits(12345678, 1, Decimal), "12.3 Mbit/s"},
+		{"bits rounded up to next prefix", HumanBits(999960, 1, Decimal), "1.0 Mbit/s"},
+		{"si", HumanSI(1200, 1, "W"), "1.2 kW"},
+		{"si fraction", HumanSI(0.35, 0, "V"), "350 mV"},
+		{"si fraction rounded up to unit", HumanSI(0.99999, 1, "V"), "1.0 V"},
+		{"si fraction rounded up to prefix", HumanSI(0.00099999, 1, "V"), "1.0 mV"},
+		{"si fraction not rounded up", HumanSI(0.9994, 1, "V"), "999.4 mV"},
+		{"zero", HumanSI(0, 1, "V"), "0 V"},
+		{"negative precision", HumanSI(1234, -1, "W"), "1 kW"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
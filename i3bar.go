@@ -1,10 +1,17 @@
 package i3bar
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"os"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -27,6 +34,91 @@ type Header struct {
 	ClickEvents bool `json:"click_events,omitempty"`
 }
 
+// ProtocolVersion is the latest i3bar protocol version known to this package.
+const ProtocolVersion = 1
+
+// maxSignal is the highest signal number supported by the kernel. (SIGRTMAX)
+const maxSignal = 64
+
+// DefaultHeader returns a Header for the latest protocol version
+// using i3bar's default stop and continue signals.
+func DefaultHeader() Header {
+	return Header{Version: ProtocolVersion}
+}
+
+// NewHeader creates a Header for the latest protocol version and validates it.
+// stop and cont may be 0 to use the i3bar defaults.
+// clickEvents defines if i3bar should send click events.
+func NewHeader(stop, cont syscall.Signal, clickEvents bool) (Header, error) {
+	h := Header{
+		Version:     ProtocolVersion,
+		StopSignal:  int(stop),
+		ContSignal:  int(cont),
+		ClickEvents: clickEvents,
+	}
+	if err := h.Validate(); err != nil {
+		return Header{}, err
+	}
+	return h, nil
+}
+
+// Validate checks the Header against the protocol rules.
+// The version has to be supported by this package and custom
+// stop and continue signals have to be signals we are able to catch
+// and must differ from each other.
+func (h Header) Validate() error {
+	return h.validate(false)
+}
+
+// validate checks the Header like Validate. If allowUnknownVersion
+// is set, versions newer than ProtocolVersion are accepted.
+func (h Header) validate(allowUnknownVersion bool) error {
+	if err := validateVersion(h.Version, allowUnknownVersion); err != nil {
+		return err
+	}
+	if err := validateSignal(h.StopSignal, syscall.SIGSTOP); err != nil {
+		return errors.Wrap(err, "invalid stop signal")
+	}
+	if err := validateSignal(h.ContSignal, syscall.SIGCONT); err != nil {
+		return errors.Wrap(err, "invalid cont signal")
+	}
+	if stop := signalOr(h.StopSignal, syscall.SIGSTOP); stop == signalOr(h.ContSignal, syscall.SIGCONT) {
+		return errors.Errorf("stop and cont signal are the same: %d", stop)
+	}
+	return nil
+}
+
+// signalOr returns sig, or def if sig is unset.
+func signalOr(sig int, def syscall.Signal) syscall.Signal {
+	if sig == 0 {
+		return def
+	}
+	return syscall.Signal(sig)
+}
+
+// validateVersion checks if version is spoken by i3bar.
+// Versions newer than ProtocolVersion are only accepted if allowUnknown is set.
+func validateVersion(version int, allowUnknown bool) error {
+	if version < 1 || (version > ProtocolVersion && !allowUnknown) {
+		return withKind(ErrProtocol, errors.Errorf("unsupported protocol version: %d", version))
+	}
+	return nil
+}
+
+// validateSignal checks if sig is either unset, the i3bar default
+// or a real signal which can be caught by our process.
+func validateSignal(sig int, def syscall.Signal) error {
+	switch {
+	case sig == 0 || syscall.Signal(sig) == def:
+		return nil
+	case sig < 0 || sig > maxSignal:
+		return errors.Errorf("unknown signal: %d", sig)
+	case syscall.Signal(sig) == syscall.SIGKILL || syscall.Signal(sig) == syscall.SIGSTOP:
+		return errors.Errorf("signal can not be caught: %d", sig)
+	}
+	return nil
+}
+
 // Alignment within a Block.
 type Alignment int
 
@@ -112,6 +204,50 @@ func (m Markup) MarshalText() ([]byte, error) {
 	return []byte(markup), nil
 }
 
+// MinWidth of a block either in pixels or as a sample text
+// whose width is used as the minimum width.
+type MinWidth struct {
+	// Pixels of the minimum width. Only used if Text is empty.
+	Pixels int
+
+	// Text representing the longest possible text of the block.
+	Text string
+}
+
+// MinWidthPixels creates a MinWidth of n pixels.
+func MinWidthPixels(n int) *MinWidth {
+	return &MinWidth{Pixels: n}
+}
+
+// MinWidthText creates a MinWidth with the width of text.
+func MinWidthText(text string) *MinWidth {
+	return &MinWidth{Text: text}
+}
+
+// UnmarshalJSON decodes either a pixel count or a sample text.
+func (m *MinWidth) UnmarshalJSON(b []byte) error {
+	var text string
+	if err := json.Unmarshal(b, &text); err == nil {
+		*m = MinWidth{Text: text}
+		return nil
+	}
+	var pixels int
+	if err := json.Unmarshal(b, &pixels); err != nil {
+		return errors.Errorf("invalid min_width: %s", string(b))
+	}
+	*m = MinWidth{Pixels: pixels}
+	return nil
+}
+
+// MarshalJSON encodes the sample text as json string
+// or the pixel count as json number.
+func (m MinWidth) MarshalJSON() ([]byte, error) {
+	if m.Text != "" {
+		return json.Marshal(m.Text)
+	}
+	return json.Marshal(m.Pixels)
+}
+
 // Block specifies a single block within a StatusLine.
 type Block struct {
 	// Name to identify this block.
@@ -123,21 +259,42 @@ type Block struct {
 	// FullText to display in this block.
 	FullText string `json:"full_text"`
 
+	// LazyText is evaluated when the block is encoded if FullText is empty.
+	// This avoids expensive text assembly for status lines which
+	// are never sent, e.g. due to rate limiting. See also TextFunc.
+	LazyText fmt.Stringer `json:"-"`
+
 	// ShortText to display if the status line needs to be shortened.
 	ShortText string `json:"short_text,omitempty"`
 
 	// Color of the text in hex. (#rrggbb)
-	Color string `json:"color,omitempty"`
+	Color Color `json:"color,omitempty"`
 
 	// Background color in hex. (#rrggbb)
-	Background string `json:"background,omitempty"`
+	Background Color `json:"background,omitempty"`
 
 	// Border color in hex. (#rrggbb)
-	Border string `json:"border,omitempty"`
+	Border Color `json:"border,omitempty"`
+
+	// BorderTop width of the border in pixels. i3bar defaults to 1.
+	// Use Int(0) to hide this edge of the border.
+	BorderTop *int `json:"border_top,omitempty"`
+
+	// BorderRight width of the border in pixels. i3bar defaults to 1.
+	// Use Int(0) to hide this edge of the border.
+	BorderRight *int `json:"border_right,omitempty"`
+
+	// BorderBottom width of the border in pixels. i3bar defaults to 1.
+	// Use Int(0) to hide this edge of the border.
+	BorderBottom *int `json:"border_bottom,omitempty"`
+
+	// BorderLeft width of the border in pixels. i3bar defaults to 1.
+	// Use Int(0) to hide this edge of the border.
+	BorderLeft *int `json:"border_left,omitempty"`
 
 	// MinWidth specifies the minimum width of the block in pixels.
 	// You can also specify a text representing the longest possible text.
-	MinWidth string `json:"min_width,omitempty"`
+	MinWidth *MinWidth `json:"min_width,omitempty"`
 
 	// Align text on the center, right or left.
 	Align Alignment `json:"align,omitempty"`
@@ -146,13 +303,35 @@ type Block struct {
 	Urgent bool `json:"urgent,omitempty"`
 
 	// Separator specifies if a separator line should be drawn after this block.
-	Separator bool `json:"separator,omitempty"`
+	// i3bar draws a separator if this is nil. Use Bool to set it.
+	Separator *bool `json:"separator,omitempty"`
 
 	// SeparatorBlockWidth specified the amount of pixels to leave black after the block.
 	SeparatorBlockWidth int `json:"separator_block_width,omitempty"`
 
 	// Markup specifies how the block should be parsed.
 	Markup Markup `json:"markup,omitempty"`
+
+	// Extra keys merged into the encoded block. This allows to use
+	// protocol extensions not yet supported by Block.
+	// Keys of regular fields always take precedence.
+	Extra map[string]interface{} `json:"-"`
+
+	// OnClick is called for click events on the block while it is part
+	// of the status line last sent. Blocks are identified by Name and Instance.
+	// Click events have to be enabled in the header, e.g. by WithClickEvents.
+	// Otherwise ErrClickEventsDisabled is reported on Stream.Errors once.
+	OnClick func(ev ClickEvent) `json:"-"`
+}
+
+// Bool returns a pointer to v for optional boolean fields like Block.Separator.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v for optional integer fields like Block.BorderTop.
+func Int(v int) *int {
+	return &v
 }
 
 // StatusLine represents a full i3bar status line.
@@ -160,69 +339,708 @@ type StatusLine []*Block
 
 // Stream represents an i3bar protocol stream.
 type Stream struct {
-	w    io.Writer
-	e    *json.Encoder
-	wMux sync.Mutex
+	out    io.Writer
+	w      *bufio.Writer
+	buf    bytes.Buffer
+	e      *json.Encoder
+	pretty bool
+	header Header
+	wMux   sync.Mutex
+	sent   bool
+	closed bool
+
+	target   Target
+	validate bool
+	strict   bool
+	plain    io.Writer
+	icons    IconSet
+	farewell StatusLine
+
+	pangoEscape bool
+
+	allowUnknownVersion bool
+
+	mwMux       sync.RWMutex
+	middlewares []Middleware
+	prototype   *Prototype
+	locale      *Locale
+
+	dedup bool
+	last  []byte
+
+	minInterval time.Duration
+	lastSent    time.Time
+	pending     StatusLine
+	timer       *time.Timer
+
+	blinkInterval   time.Duration
+	newTicker       func(time.Duration) (<-chan time.Time, func())
+	blinkColor      Color
+	blinkBackground Color
+	blinkLine       StatusLine
+	blinkOff        bool
+	blinking        bool
+	pendingBlink    bool
+
+	asyncInterval time.Duration
+	aMux          sync.Mutex
+	latest        StatusLine
+	notify        chan struct{}
+
+	rMux         sync.Mutex
+	events       chan ClickEvent
+	stopRead     chan struct{}
+	scrollWindow time.Duration
+
+	doubleClickWindow time.Duration
 
-	r io.Reader
-	d *json.Decoder
+	clickReader      bool
+	clicksReported   bool
+	clickHandlers    []func(ClickEvent)
+	clickMiddlewares []ClickMiddleware
+	routes           map[clickRoute]func(ClickEvent)
+	bindings         map[clickRoute][]binding
+	blockRoutes      map[clickRoute]func(ClickEvent)
+
+	replaySize   int
+	replayAge    time.Duration
+	replay       []replayEvent
+	replayQueue  []ClickEvent
+	replayNotify chan struct{}
+	eventsTaken  bool
+
+	asyncClicks bool
+	clickSlots  chan struct{}
+	qMux        sync.Mutex
+	queues      map[clickRoute]*clickQueue
+
+	statsMux      sync.Mutex
+	clickStats    map[clickRoute]*ClickStats
+	onClickMetric func(ClickMetric)
+
+	sigs     chan os.Signal
+	paused   bool
+	onPause  func()
+	onResume func()
+	reload   chan os.Signal
+
+	refreshSignal    os.Signal
+	refresh          chan os.Signal
+	refreshListeners []func()
+
+	onDisconnect func(error)
+
+	errs chan error
+
+	// done is closed once the stream is closed or disconnected,
+	// which stops all goroutines of the stream. It is renewed
+	// by Reconnect after a disconnect.
+	ctx       context.Context
+	dMux      sync.Mutex
+	done      chan struct{}
+	closeOnce *sync.Once
+	shut      bool
 }
 
 // NewStream initializes a new i3bar protocol stream with specified parameters.
 // w is the io.Writer where to send the infinite Block json array.
-// r is the io.Reader where to read the infinite ClickEvent json array. (TBD)
+// r is the io.Reader where to read the infinite ClickEvent json array.
+// r may be nil if you are not interested in click events.
 // pretty can be true if you want the json encoder to pretty-print the json.
 // h is the Header which is used to initialize the i3bar protocol.
-func NewStream(w io.Writer, r io.Reader, pretty bool, h Header) (*Stream, error) {
+// If h declares custom stop or continue signals, handlers for them are
+// installed, so that the stream pauses sending status lines between them.
+// opts can be used to further configure the stream.
+func NewStream(w io.Writer, r io.Reader, pretty bool, h Header, opts ...Option) (*Stream, error) {
+	return NewStreamContext(context.Background(), w, r, pretty, h, opts...)
+}
+
+// NewStreamContext initializes a new i3bar protocol stream like NewStream
+// but ties the lifetime of the stream to ctx.
+// Once ctx is cancelled the click event reader is stopped, pending output
+// is flushed and the infinite json array is closed.
+func NewStreamContext(ctx context.Context, w io.Writer, r io.Reader, pretty bool, h Header, opts ...Option) (*Stream, error) {
 	stream := &Stream{
-		w:    w,
-		e:    json.NewEncoder(w),
-		wMux: sync.Mutex{},
-		r:    r,
-		d:    json.NewDecoder(r),
+		out:    w,
+		w:      bufio.NewWriter(w),
+		header: h,
+		wMux:   sync.Mutex{},
+		errs:   make(chan error, errorBacklog),
+		ctx:    ctx,
+		done:   make(chan struct{}),
+		icons:  FontAwesome,
+
+		newTicker: newTicker,
+		closeOnce: &sync.Once{},
+	}
+
+	for _, opt := range opts {
+		opt(stream)
 	}
 
-	if pretty {
-		stream.e.SetIndent("", "    ")
+	// i3bar falls back to plain text for versions it does not know
+	if err := validateVersion(stream.header.Version, stream.allowUnknownVersion); err != nil {
+		return nil, errors.Wrap(err, "Invalid header")
 	}
+	if (len(stream.clickHandlers) > 0 || stream.clickReader) && r == nil {
+		return nil, errors.New("click handlers require a reader")
+	}
+
+	stream.pretty = pretty
+	stream.e = stream.newEncoder(&stream.buf)
+
+	if err := stream.handshake(); err != nil {
+		return nil, err
+	}
+
+	// custom signals terminate the process unless they are handled
+	stream.handleSignals(syscall.Signal(stream.header.StopSignal), syscall.Signal(stream.header.ContSignal))
+	if stream.refreshSignal != nil {
+		stream.handleRefresh(stream.refreshSignal)
+	}
+
+	stream.start(r)
+	if r == nil {
+		stream.events = make(chan ClickEvent)
+		close(stream.events)
+	}
+
+	return stream, nil
+}
+
+// start runs the goroutines of the stream until it is closed or disconnected.
+// Click events are read from r if it is not nil.
+func (s *Stream) start(r io.Reader) {
+	done := s.stopped()
+
+	// start writer for asynchronous status lines
+	if s.asyncInterval > 0 {
+		go s.writeAsync(done)
+	}
+
+	// start blinking urgent blocks
+	if s.blinkInterval > 0 {
+		go s.blink(done)
+	}
+
+	// start reader on infinite click event array
+	if r != nil {
+		s.startReader(r, done)
+	}
+
+	// close stream when context is done
+	if s.ctx.Done() != nil {
+		go func() {
+			select {
+			case <-s.ctx.Done():
+				if err := s.Close(); err != nil {
+					s.reportError(err)
+				}
+			case <-done:
+			}
+		}()
+	}
+}
+
+// stopped returns the channel which is closed once the stream
+// is closed or disconnected.
+func (s *Stream) stopped() <-chan struct{} {
+	s.dMux.Lock()
+	defer s.dMux.Unlock()
+	return s.done
+}
+
+// stop closes done once, so that all goroutines of the stream return.
+func (s *Stream) stop() {
+	s.dMux.Lock()
+	done, once := s.done, s.closeOnce
+	s.dMux.Unlock()
+	once.Do(func() { close(done) })
+}
 
+// handshake sends the protocol header and starts the infinite json array.
+// The caller has to hold wMux.
+func (s *Stream) handshake() error {
 	// send protocol header
-	if err := stream.e.Encode(h); err != nil {
-		return nil, errors.Wrap(err, "Failed to send header")
+	data, err := s.marshal(s.header)
+	if err == nil {
+		err = s.write(data)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to send header")
 	}
 
 	// start infinite loop on writer
-	if _, err := w.Write([]byte("[")); err != nil {
-		return nil, errors.Wrap(err, "Failed to start infinite json array")
+	if _, err := s.w.Write([]byte("[")); err != nil {
+		return errors.Wrap(s.checkWrite(err), "Failed to start infinite json array")
 	}
+	return s.flush()
+}
 
-	// TODO: start reader
+// Events returns the channel on which click events sent by i3bar are delivered.
+// The channel is closed when the underlying reader is exhausted,
+// the click event stream could not be parsed anymore or the
+// stream got disconnected or reconnected to another reader.
+//
+// Once Events has been called, click events without handler are sent
+// on the channel and wait for being received, even with WithClickReplay.
+func (s *Stream) Events() <-chan ClickEvent {
+	s.rMux.Lock()
+	defer s.rMux.Unlock()
+	s.eventsTaken = true
+	return s.events
+}
 
-	return stream, nil
+// errorBacklog is the amount of asynchronous errors kept
+// until they are received from the Errors channel.
+const errorBacklog = 16
+
+// Icons returns the icon set of the stream selected by WithIcons,
+// e.g. to render the icons of blocks.
+func (s *Stream) Icons() IconSet {
+	return s.icons
+}
+
+// Errors returns the channel on which errors are delivered which
+// happen outside of a method call, e.g. while decoding click events.
+// Errors are dropped if the channel is full. The channel is never closed.
+func (s *Stream) Errors() <-chan error {
+	return s.errs
+}
+
+// reportError delivers err to the errors channel without blocking.
+func (s *Stream) reportError(err error) {
+	select {
+	case s.errs <- err:
+	default:
+	}
+}
+
+// startReader starts reading click events from r on a new events channel
+// until done is closed. A previously started reader is stopped.
+func (s *Stream) startReader(r io.Reader, done <-chan struct{}) {
+	events := make(chan ClickEvent)
+	stop := make(chan struct{})
+
+	s.rMux.Lock()
+	if s.stopRead != nil {
+		close(s.stopRead)
+	}
+	s.events, s.stopRead = events, stop
+	s.rMux.Unlock()
+
+	go s.readEvents(newElementReader(r), events, stop, done)
+}
+
+// readEvents delivers all click events decoded by d to events
+// until the click event stream ends or stop or done is closed.
+func (s *Stream) readEvents(er *elementReader, events chan<- ClickEvent, stop, done <-chan struct{}) {
+	defer close(events)
+
+	raw := make(chan ClickEvent)
+	go s.decodeEvents(er, raw, stop, done)
+
+	// replayed events already passed the click middlewares
+	replay := func() bool {
+		for _, ev := range s.takeQueued() {
+			if !s.dispatch(ev, clickRoute{name: ev.Name, instance: ev.Instance}, events, stop, done) {
+				return false
+			}
+		}
+		return true
+	}
+
+	deliver := func(ev ClickEvent) bool {
+		// queued replays arrived before ev
+		if !replay() {
+			return false
+		}
+		key := clickRoute{name: ev.Name, instance: ev.Instance}
+		s.countClick(key, ClickReceived, 0)
+		dispatched, ok := false, true
+		s.handleClick(ev, func(ev ClickEvent) {
+			// counted for the received event, even if middlewares changed it
+			dispatched = true
+			ok = s.dispatch(ev, key, events, stop, done)
+		})
+		if !dispatched {
+			s.countClick(key, ClickDropped, 0)
+		}
+		return ok
+	}
+
+	// scroll events coalesced within scrollWindow
+	var scroll *ClickEvent
+	var flush <-chan time.Time
+	// click held back for doubleClickWindow
+	var click *ClickEvent
+	var clickFlush <-chan time.Time
+
+	// at most one of them is held back at a time
+	deliverScroll := func() bool {
+		if scroll == nil {
+			return true
+		}
+		ev := *scroll
+		scroll, flush = nil, nil
+		return deliver(ev)
+	}
+	deliverClick := func() bool {
+		if click == nil {
+			return true
+		}
+		ev := *click
+		click, clickFlush = nil, nil
+		return deliver(ev)
+	}
+
+	for {
+		select {
+		case ev, ok := <-raw:
+			if !ok {
+				if deliverScroll() {
+					deliverClick()
+				}
+				return
+			}
+			if s.scrollWindow > 0 && ev.Button.IsScroll() {
+				if !deliverClick() {
+					return
+				}
+				if scroll != nil && sameButton(*scroll, ev) {
+					scroll.Delta++
+					continue
+				}
+				if !deliverScroll() {
+					return
+				}
+				ev.Delta = 1
+				scroll, flush = &ev, time.After(s.scrollWindow)
+				continue
+			}
+			if !deliverScroll() {
+				return
+			}
+			if s.doubleClickWindow > 0 && !ev.Button.IsScroll() {
+				if click != nil && sameButton(*click, ev) {
+					click, clickFlush = nil, nil
+					ev.Double = true
+					if !deliver(ev) {
+						return
+					}
+					continue
+				}
+				if !deliverClick() {
+					return
+				}
+				click, clickFlush = &ev, time.After(s.doubleClickWindow)
+				continue
+			}
+			if !deliverClick() || !deliver(ev) {
+				return
+			}
+		case <-flush:
+			if !deliverScroll() {
+				return
+			}
+		case <-clickFlush:
+			if !deliverClick() {
+				return
+			}
+		case <-s.replayNotify:
+			if !replay() {
+				return
+			}
+		case <-stop:
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// sameButton reports whether a and b are events of the same button
+// on the same block.
+func sameButton(a, b ClickEvent) bool {
+	return a.Name == b.Name && a.Instance == b.Instance && a.Button == b.Button
+}
+
+// decodeEvents reads the infinite ClickEvent json array from er
+// and sends every event to raw until stop or done is closed.
+// Malformed click events are reported and skipped.
+func (s *Stream) decodeEvents(er *elementReader, raw chan<- ClickEvent, stop, done <-chan struct{}) {
+	defer close(raw)
+
+	for {
+		msg, offset, err := er.next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if errors.Is(err, ErrProtocol) {
+				s.reportError(err)
+			} else {
+				s.reportError(errors.Wrap(err, "Failed to read click event stream"))
+			}
+			return
+		}
+
+		var ev ClickEvent
+		if err := json.Unmarshal(msg, &ev); err != nil {
+			s.reportError(&DecodeError{Offset: offset, Raw: msg, Err: err})
+			continue
+		}
+		select {
+		case raw <- ev:
+		case <-stop:
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+// isEndOfStream reports whether err was caused by the other side
+// ending an infinite json array without the closing bracket.
+func isEndOfStream(err error) bool {
+	if err == io.EOF {
+		return true
+	}
+	// json.Decoder reports io.ErrUnexpectedEOF if the stream ends within
+	// an element, e.g. because the producer got killed, but a syntax
+	// error if it ends right after the comma separating two elements
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var serr *json.SyntaxError
+	return errors.As(err, &serr) && serr.Error() == "unexpected end of JSON input"
 }
 
 // SendLine sends a new status line to the underlying stream.
 // This function is thread safe.
 func (s *Stream) SendLine(b StatusLine) error {
+	if s.asyncInterval > 0 {
+		return s.sendAsync(b)
+	}
+
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	b, err := s.prepare(b)
+	if err != nil {
+		return errors.Wrap(err, "Failed to send status line")
+	}
+	if s.paused {
+		s.hold(b)
+		return nil
+	}
+	if s.throttle(b) {
+		return nil
+	}
+	if err := s.encodeLine(b); err != nil {
+		return errors.Wrap(err, "Failed to send status line")
+	}
+	return nil
+}
+
+// encodeLine encodes a prepared status line and writes it to the stream.
+// Lazy texts of its blocks are evaluated at this point.
+// The caller has to hold wMux.
+func (s *Stream) encodeLine(b StatusLine) error {
+	b = s.blinkBlocks(b)
+	data, err := s.marshal(b)
+	if err != nil {
+		return err
+	}
+	s.registerBlocks(b)
+	if s.dedup && s.sent && bytes.Equal(data, s.last) {
+		return nil
+	}
+	if err := s.writeLine(data); err != nil {
+		return err
+	}
+	if s.plain != nil && !s.blinking {
+		// the plain text output must never break the i3bar output
+		if _, err := io.WriteString(s.plain, PlainText(b, s.icons)+"\n"); err != nil {
+			s.reportError(errors.Wrap(err, "Failed to write plain text status line"))
+		}
+	}
+	return nil
+}
+
+// prepare validates and applies all stream-level transformations to
+// a status line before it is encoded.
+// The status line of the caller is never modified.
+func (s *Stream) prepare(b StatusLine) (StatusLine, error) {
+	s.mwMux.RLock()
+	middlewares := s.middlewares
+	s.mwMux.RUnlock()
+	if len(middlewares) > 0 {
+		// middlewares are free to modify the status line
+		b = b.Clone()
+		for _, mw := range middlewares {
+			b = mw(b)
+		}
+	}
+
+	if s.pangoEscape {
+		b = escapePango(b)
+	}
+	if s.validate || s.strict {
+		if err := b.validate(s.strict, s.target == Swaybar); err != nil {
+			return nil, withKind(ErrEncode, err)
+		}
+	}
+	if s.target != Swaybar {
+		b = stripAlpha(b)
+	}
+	return b, nil
+}
+
+// writeLine writes an encoded status line to the stream and flushes it.
+// The caller has to hold wMux.
+func (s *Stream) writeLine(data []byte) error {
+	// separate status lines within the infinite array
+	if s.sent {
+		if err := s.write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	if err := s.write(data); err != nil {
+		return err
+	}
+	s.sent = true
+	s.lastSent = time.Now()
+	// the last status line is kept for dedup and reconnects
+	s.last = append(s.last[:0], data...)
+	return s.flush()
+}
+
+// newEncoder creates a json encoder on w honoring the pretty setting.
+func (s *Stream) newEncoder(w io.Writer) *json.Encoder {
+	e := json.NewEncoder(w)
+	if s.pretty {
+		e.SetIndent("", "    ")
+	}
+	return e
+}
+
+// marshal encodes v into the internal buffer, so that partially
+// encoded values never hit the stream.
+// The returned slice is only valid until the next call.
+// The caller has to hold wMux.
+func (s *Stream) marshal(v interface{}) ([]byte, error) {
+	s.buf.Reset()
+	if err := s.e.Encode(v); err != nil {
+		return nil, withKind(ErrEncode, err)
+	}
+	return s.buf.Bytes(), nil
+}
+
+// write writes p to the buffered writer.
+// The caller has to hold wMux.
+func (s *Stream) write(p []byte) error {
+	if _, err := s.w.Write(p); err != nil {
+		return errors.Wrap(s.checkWrite(err), "Failed to write json stream")
+	}
+	return nil
+}
+
+// SendLineContext sends a new status line to the underlying stream
+// like SendLine, but gives up waiting once ctx is done.
+// This is useful if the writer blocks, e.g. because i3bar got stopped
+// or the pipe is full. Note that an aborted status line may still be
+// written once the writer unblocks.
+// This function is thread safe.
+func (s *Stream) SendLineContext(ctx context.Context, b StatusLine) error {
+	if ctx.Done() == nil {
+		return s.SendLine(b)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.SendLine(b)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "Failed to send status line in time")
+	}
+}
+
+// Flush writes any buffered data to the underlying writer.
+// If the underlying writer is buffered as well (e.g. a *bufio.Writer)
+// it is flushed too.
+// This function is thread safe.
+func (s *Stream) Flush() error {
 	s.wMux.Lock()
 	defer s.wMux.Unlock()
-	if err := s.e.Encode(b); err != nil {
-		return errors.Wrap(err, "Failed to encode status line into json stream")
+	if s.closed {
+		return ErrClosed
+	}
+	return s.flush()
+}
+
+// flush writes any buffered data to the underlying writer.
+// The caller has to hold wMux.
+func (s *Stream) flush() error {
+	if err := s.w.Flush(); err != nil {
+		return errors.Wrap(s.checkWrite(err), "Failed to flush json stream")
+	}
+	if f, ok := s.out.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return errors.Wrap(s.checkWrite(err), "Failed to flush underlying writer")
+		}
 	}
 	return nil
 }
 
 // Close closes the underlying stream by issuing an ]
-// to close the infinite json array.
+// to close the infinite json array. The latest status line held back
+// by WithMinInterval, WithAsync or Pause is sent before, even if the
+// stream is paused.
 //
-// This function is thread safe although you don't want to call
-// this multiple times. Also you don't want to call any other method
-// after this.
+// This function is thread safe and idempotent. Calling any other
+// method which writes to the stream after this returns ErrClosed.
 func (s *Stream) Close() error {
+	return s.close(nil)
+}
+
+// close stops the stream and closes the infinite json array.
+// If farewell is not nil, it supersedes any pending status line.
+func (s *Stream) close(farewell StatusLine) error {
+	s.dMux.Lock()
+	s.shut = true
+	s.dMux.Unlock()
+	s.stop()
+
 	s.wMux.Lock()
 	defer s.wMux.Unlock()
+	s.stopSignals()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if farewell != nil {
+		if err := s.sendFarewell(farewell); err != nil {
+			return errors.Wrap(err, "Failed to send farewell status line")
+		}
+	}
+	if err := s.flushPending(); err != nil {
+		return errors.Wrap(err, "Failed to send pending status line")
+	}
+	if err := s.flushAsync(); err != nil {
+		return errors.Wrap(err, "Failed to send pending status line")
+	}
 	if _, err := s.w.Write([]byte("]")); err != nil {
-		return errors.Wrap(err, "Failed to close infinite json array")
+		return errors.Wrap(s.checkWrite(err), "Failed to close infinite json array")
 	}
-	return nil
+	return s.flush()
 }
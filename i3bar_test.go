@@ -0,0 +1,461 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestStreamFraming(t *testing.T) {
+	tests := []struct {
+		name   string
+		pretty bool
+		header Header
+		lines  []StatusLine
+		want   string
+	}{
+		{
+			name:   "no status lines",
+			header: DefaultHeader(),
+			want:   "{\"version\":1}\n[]",
+		},
+		{
+			name:   "single status line",
+			header: DefaultHeader(),
+			lines:  []StatusLine{{{FullText: "a"}}},
+			want:   "{\"version\":1}\n[[{\"full_text\":\"a\"}]\n]",
+		},
+		{
+			name:   "comma between status lines",
+			header: Header{Version: 1, ClickEvents: true},
+			lines: []StatusLine{
+				{{Name: "a", FullText: "1"}},
+				{{Name: "a", FullText: "2"}, {Name: "b", FullText: "3"}},
+				{},
+			},
+			want: "{\"version\":1,\"click_events\":true}\n[" +
+				"[{\"name\":\"a\",\"full_text\":\"1\"}]\n" +
+				",[{\"name\":\"a\",\"full_text\":\"2\"},{\"name\":\"b\",\"full_text\":\"3\"}]\n" +
+				",[]\n" +
+				"]",
+		},
+		{
+			name:   "custom signals",
+			header: Header{Version: 1, StopSignal: 10, ContSignal: 12},
+			lines:  []StatusLine{{{FullText: "a"}}},
+			want:   "{\"version\":1,\"stop_signal\":10,\"cont_signal\":12}\n[[{\"full_text\":\"a\"}]\n]",
+		},
+		{
+			name:   "pretty",
+			pretty: true,
+			header: DefaultHeader(),
+			lines:  []StatusLine{{{FullText: "a"}}, {{FullText: "b"}}},
+			want: "{\n    \"version\": 1\n}\n[" +
+				"[\n    {\n        \"full_text\": \"a\"\n    }\n]\n" +
+				",[\n    {\n        \"full_text\": \"b\"\n    }\n]\n" +
+				"]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, tt.pretty, tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range tt.lines {
+				if err := s.SendLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// the stream has to be valid for strict parsers
+			d := json.NewDecoder(&out)
+			var h Header
+			if err := d.Decode(&h); err != nil {
+				t.Fatalf("invalid header: %v", err)
+			}
+			var lines []StatusLine
+			if err := d.Decode(&lines); err != nil {
+				t.Fatalf("invalid status line array: %v", err)
+			}
+			if len(lines) != len(tt.lines) {
+				t.Errorf("decoded %d status lines, want %d", len(lines), len(tt.lines))
+			}
+			if d.More() {
+				t.Error("trailing data after status line array")
+			}
+		})
+	}
+}
+
+func TestStreamClickFraming(t *testing.T) {
+	left := ClickEvent{Name: "cpu", Button: LeftClick, X: 1800, Y: 10, RelativeX: 12, RelativeY: 8, Width: 60, Height: 20}
+	scroll := ClickEvent{Name: "vol", Instance: "alsa,0 [main]", Button: ScrollUp, Modifiers: Modifiers(Shift | Mod2), X: 1700, Y: 12}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []ClickEvent
+	}{
+		{
+			// i3bar opens the array on a line of its own and yajl
+			// prepends the comma to every event after the first
+			name: "i3bar leading comma",
+			input: "[\n" +
+				`{"name":"cpu","button":1,"modifiers":[],"x":1800,"y":10,"relative_x":12,"relative_y":8,"width":60,"height":20}` + "\n" +
+				`,{"name":"vol","instance":"alsa,0 [main]","button":4,"modifiers":["Shift","Mod2"],"x":1700,"y":12}` + "\n",
+			want: []ClickEvent{left, scroll},
+		},
+		{
+			// swaybar terminates every event with a comma
+			name: "swaybar inline comma",
+			input: "[\n" +
+				`{"name":"cpu","button":1,"modifiers":[],"x":1800,"y":10,"relative_x":12,"relative_y":8,"width":60,"height":20},` + "\n" +
+				`{"name":"vol","instance":"alsa,0 [main]","button":4,"modifiers":["Shift","Mod2"],"x":1700,"y":12},` + "\n",
+			want: []ClickEvent{left, scroll},
+		},
+		{
+			name: "closed array",
+			input: `[{"name":"cpu","button":1,"x":1800,"y":10,"relative_x":12,"relative_y":8,"width":60,"height":20},` +
+				`{"name":"vol","instance":"alsa,0 [main]","button":4,"modifiers":["Shift","Mod2"],"x":1700,"y":12}]`,
+			want: []ClickEvent{left, scroll},
+		},
+		{
+			name:  "empty array",
+			input: "[\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := DefaultHeader()
+			h.ClickEvents = true
+			s, err := NewStream(&bytes.Buffer{}, strings.NewReader(tt.input), false, h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			var got []ClickEvent
+			for ev := range s.Events() {
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			select {
+			case err := <-s.Errors():
+				t.Errorf("unexpected error: %v", err)
+			default:
+			}
+		})
+	}
+}
+
+func TestHeaderValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header Header
+		err    string
+	}{
+		{"default", DefaultHeader(), ""},
+		{"custom signals", Header{Version: 1, StopSignal: 10, ContSignal: 12}, ""},
+		{"custom stop signal", Header{Version: 1, StopSignal: 10}, ""},
+		{"default signals", Header{Version: 1, StopSignal: int(syscall.SIGSTOP), ContSignal: int(syscall.SIGCONT)}, ""},
+		{"same custom signals", Header{Version: 1, StopSignal: 10, ContSignal: 10}, "stop and cont signal are the same: 10"},
+		{"stop signal is default cont signal", Header{Version: 1, StopSignal: int(syscall.SIGCONT)}, "stop and cont signal are the same"},
+		{"cont signal is default stop signal", Header{Version: 1, ContSignal: int(syscall.SIGSTOP)}, "invalid cont signal"},
+		{"uncatchable stop signal", Header{Version: 1, StopSignal: int(syscall.SIGKILL)}, "invalid stop signal"},
+		{"unknown signal", Header{Version: 1, ContSignal: -1}, "invalid cont signal"},
+		{"invalid version", Header{Version: 0}, "unsupported protocol version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.header.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDedup(t *testing.T) {
+	a := StatusLine{{Name: "a", FullText: "1"}}
+	b := StatusLine{{Name: "a", FullText: "2"}}
+	lazy := StatusLine{{Name: "a", LazyText: TextFunc(func() string { return "1" })}}
+
+	tests := []struct {
+		name  string
+		dedup bool
+		lines []StatusLine
+		want  int
+	}{
+		{"disabled", false, []StatusLine{a, a, a}, 3},
+		{"consecutive duplicates", true, []StatusLine{a, a, a}, 1},
+		{"changes", true, []StatusLine{a, b, a}, 3},
+		{"duplicates between changes", true, []StatusLine{a, a, b, b, a}, 3},
+		// duplicates are detected by the encoding, including lazy texts
+		{"lazy text", true, []StatusLine{a, lazy}, 1},
+		{"empty lines", true, []StatusLine{{}, {}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var opts []Option
+			if tt.dedup {
+				opts = append(opts, WithDedup())
+			}
+			s, err := NewStream(&out, nil, false, DefaultHeader(), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range tt.lines {
+				if err := s.SendLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			d := json.NewDecoder(&out)
+			var h Header
+			if err := d.Decode(&h); err != nil {
+				t.Fatal(err)
+			}
+			var lines []StatusLine
+			if err := d.Decode(&lines); err != nil {
+				t.Fatal(err)
+			}
+			if len(lines) != tt.want {
+				t.Errorf("sent %d status lines, want %d", len(lines), tt.want)
+			}
+		})
+	}
+}
+
+// flushRecorder records the data written to it and how often it got flushed.
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+	err     error
+}
+
+func (f *flushRecorder) Flush() error {
+	f.flushes++
+	return f.err
+}
+
+func TestFlush(t *testing.T) {
+	var out flushRecorder
+	s, err := NewStream(&out, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// the header and every status line reach the writer right away
+	if got := out.String(); got != "{\"version\":1}\n[" {
+		t.Errorf("header not flushed: %q", got)
+	}
+	flushes := out.flushes
+	if flushes == 0 {
+		t.Error("underlying writer not flushed after the header")
+	}
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "[{\"full_text\":\"a\"}]\n") {
+		t.Errorf("status line not flushed: %q", out.String())
+	}
+	if out.flushes <= flushes {
+		t.Error("underlying writer not flushed after the status line")
+	}
+
+	flushes = out.flushes
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if out.flushes != flushes+1 {
+		t.Errorf("Flush flushed the underlying writer %d times, want once", out.flushes-flushes)
+	}
+
+	out.err = errors.New("flush failed")
+	if err := s.Flush(); err == nil || !strings.Contains(err.Error(), "flush failed") {
+		t.Errorf("got %v, want the error of the underlying writer", err)
+	}
+	out.err = nil
+
+	s.Close()
+	if err := s.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close: got %v, want ErrClosed", err)
+	}
+}
+
+func TestStreamContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r, w := io.Pipe()
+	defer w.Close()
+	var out lockedBuffer
+	s, err := NewStreamContext(ctx, &out, r, false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	events := s.Events()
+
+	cancel()
+	// the click event reader is stopped and the array is closed
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("received click event after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("events not closed after cancel")
+	}
+	waitFor(t, "closed array", func() bool { return strings.HasSuffix(out.String(), "\n]") })
+	if err := s.SendLine(StatusLine{{FullText: "b"}}); !errors.Is(err, ErrClosed) {
+		t.Errorf("SendLine after cancel: got %v, want ErrClosed", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close after cancel: %v", err)
+	}
+	if got := strings.Count(out.String(), "]"); got != 2 {
+		t.Errorf("got %q, want the array closed once", out.String())
+	}
+}
+
+// blockingWriter blocks all writes after the first n until release is closed.
+type blockingWriter struct {
+	lockedBuffer
+	n       int32
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	if atomic.AddInt32(&w.n, -1) < 0 {
+		<-w.release
+	}
+	return w.lockedBuffer.Write(p)
+}
+
+func TestSendLineContext(t *testing.T) {
+	// the header is written with a single write
+	w := &blockingWriter{n: 1, release: make(chan struct{})}
+	s, err := NewStream(w, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.SendLineContext(cancelled, StatusLine{{FullText: "a"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled context: got %v, want context.Canceled", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.SendLineContext(ctx, StatusLine{{FullText: "b"}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("blocked writer: got %v, want context.DeadlineExceeded", err)
+	}
+
+	// the aborted status line is written once the writer unblocks
+	close(w.release)
+	if err := s.SendLineContext(context.Background(), StatusLine{{FullText: "c"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusLines(w.String()), []string{`[[{"full_text":"b"}]`, `,[{"full_text":"c"}]`, "]"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got status lines %q, want %q", got, want)
+	}
+}
+
+func TestErrorsBacklog(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// reporting never blocks, errors beyond the backlog are dropped
+	for i := 0; i < errorBacklog+5; i++ {
+		s.reportError(fmt.Errorf("error %d", i))
+	}
+	for i := 0; i < errorBacklog; i++ {
+		select {
+		case err := <-s.Errors():
+			if want := fmt.Sprintf("error %d", i); err.Error() != want {
+				t.Errorf("got %v, want %s", err, want)
+			}
+		default:
+			t.Fatalf("got %d errors, want %d", i, errorBacklog)
+		}
+	}
+	select {
+	case err := <-s.Errors():
+		t.Errorf("got %v beyond the backlog", err)
+	default:
+	}
+}
+
+func TestCloseIdempotent(t *testing.T) {
+	var out lockedBuffer
+	s, err := NewStream(&out, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// concurrent and repeated calls close the array once
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Close(); err != nil {
+				t.Errorf("Close: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := s.Close(); err != nil {
+		t.Errorf("Close after Close: %v", err)
+	}
+	if got := out.String(); got != "{\"version\":1}\n[]" {
+		t.Errorf("got %q, want the array closed once", got)
+	}
+
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); !errors.Is(err, ErrClosed) {
+		t.Errorf("SendLine after Close: got %v, want ErrClosed", err)
+	}
+	if err := s.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close: got %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,137 @@
+package i3bar
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IconSet maps semantic icon names like "wifi" or "battery-75"
+// to the glyphs of an icon font.
+type IconSet map[string]string
+
+// FontAwesome contains Font Awesome 5 glyphs for commonly used blocks.
+var FontAwesome = IconSet{
+	"battery":     "\uf240",
+	"battery-0":   "\uf244",
+	"battery-25":  "\uf243",
+	"battery-50":  "\uf242",
+	"battery-75":  "\uf241",
+	"battery-100": "\uf240",
+	"bolt":        "\uf0e7",
+	"calendar":    "\uf073",
+	"clock":       "\uf017",
+	"cpu":         "\uf2db",
+	"disk":        "\uf0a0",
+	"memory":      "\uf538",
+	"music":       "\uf001",
+	"mute":        "\uf6a9",
+	"network":     "\uf6ff",
+	"volume":      "\uf028",
+	"wifi":        "\uf1eb",
+}
+
+// NerdFonts contains Nerd Fonts glyphs for commonly used blocks.
+var NerdFonts = IconSet{
+	"battery":     "\U000f0079",
+	"battery-0":   "\U000f008e",
+	"battery-25":  "\U000f007b",
+	"battery-50":  "\U000f007e",
+	"battery-75":  "\U000f0080",
+	"battery-100": "\U000f0079",
+	"bolt":        "\uf0e7",
+	"calendar":    "\uf073",
+	"clock":       "\uf017",
+	"cpu":         "\uf4bc",
+	"disk":        "\uf0a0",
+	"memory":      "\U000f035b",
+	"music":       "\uf001",
+	"mute":        "\U000f0581",
+	"network":     "\U000f0200",
+	"volume":      "\uf028",
+	"wifi":        "\uf1eb",
+}
+
+// Emoji contains emoji for commonly used blocks,
+// which need no icon font at all.
+var Emoji = IconSet{
+	"battery":     "🔋",
+	"battery-0":   "🪫",
+	"battery-25":  "🔋",
+	"battery-50":  "🔋",
+	"battery-75":  "🔋",
+	"battery-100": "🔋",
+	"bolt":        "⚡",
+	"calendar":    "📅",
+	"clock":       "🕒",
+	"cpu":         "💻",
+	"disk":        "💾",
+	"memory":      "🧠",
+	"music":       "🎵",
+	"mute":        "🔇",
+	"network":     "🖧",
+	"volume":      "🔊",
+	"wifi":        "📶",
+}
+
+// With returns a copy of the IconSet with the icons of overrides
+// added or replaced, e.g. to customize single icons of a set.
+func (set IconSet) With(overrides IconSet) IconSet {
+	merged := make(IconSet, len(set)+len(overrides))
+	for name, glyph := range set {
+		merged[name] = glyph
+	}
+	for name, glyph := range overrides {
+		merged[name] = glyph
+	}
+	return merged
+}
+
+// Icon returns the glyph of the icon name
+// or an empty string if the icon is unknown.
+func (set IconSet) Icon(name string) string {
+	glyph, _ := set.icon(name)
+	return glyph
+}
+
+// icon returns the glyph of the icon name.
+func (set IconSet) icon(name string) (string, error) {
+	glyph, ok := set[name]
+	if !ok {
+		return "", errors.Errorf("unknown icon: %s", name)
+	}
+	return glyph, nil
+}
+
+// names returns a replacer of all glyphs of the set by their names,
+// e.g. "[battery]". A glyph shared by several icons is named after the
+// shortest of their names, which is the most generic one in the sets
+// of this package, e.g. "battery" instead of "battery-25".
+func (set IconSet) names() *strings.Replacer {
+	named := map[string]string{}
+	for name, glyph := range set {
+		if glyph == "" {
+			continue
+		}
+		if prev, ok := named[glyph]; !ok || len(name) < len(prev) || (len(name) == len(prev) && name < prev) {
+			named[glyph] = name
+		}
+	}
+	glyphs := make([]string, 0, len(named))
+	for glyph := range named {
+		glyphs = append(glyphs, glyph)
+	}
+	// longer glyphs first, so that glyphs starting with another glyph match
+	sort.Slice(glyphs, func(i, j int) bool {
+		if len(glyphs[i]) != len(glyphs[j]) {
+			return len(glyphs[i]) > len(glyphs[j])
+		}
+		return glyphs[i] < glyphs[j]
+	})
+	pairs := make([]string, 0, 2*len(glyphs))
+	for _, glyph := range glyphs {
+		pairs = append(pairs, glyph, "["+named[glyph]+"]")
+	}
+	return strings.NewReplacer(pairs...)
+}
@@ -0,0 +1,86 @@
+package i3bar
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPlainTextIcons(t *testing.T) {
+	line := StatusLine{
+		{FullText: Emoji.Icon("battery-25") + " 25%"},
+		{FullText: Emoji.Icon("battery-0") + " 0%"},
+		{FullText: FontAwesome.Icon("battery-100") + " 100%"},
+	}
+	tests := []struct {
+		name  string
+		icons IconSet
+		want  string
+	}{
+		{"emoji", Emoji, "[battery] 25% | [battery-0] 0% |  100%"},
+		{"font awesome", FontAwesome, "🔋 25% | 🪫 0% | [battery] 100%"},
+		{"override", Emoji.With(IconSet{"battery-25": "🔋", "battery": "🔌"}), "[battery-25] 25% | [battery-0] 0% |  100%"},
+		{"nil", nil, "🔋 25% | 🪫 0% |  100%"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				if got := PlainText(line, tt.icons); got != tt.want {
+					t.Fatalf("got %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithIcons(t *testing.T) {
+	var plainA, plainB bytes.Buffer
+	a, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithPlainText(&plainA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithPlainText(&plainB), WithIcons(Emoji))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if got := a.Icons().Icon("wifi"); got != FontAwesome["wifi"] {
+		t.Errorf("default icon set: got %q", got)
+	}
+	if got := b.Icons().Icon("wifi"); got != Emoji["wifi"] {
+		t.Errorf("selected icon set: got %q", got)
+	}
+	line := StatusLine{{FullText: FontAwesome["wifi"] + " " + Emoji["wifi"]}}
+	if err := a.SendLine(line); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SendLine(line); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := plainA.String(), "[wifi] 📶\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := plainB.String(), FontAwesome["wifi"]+" [wifi]\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestTemplateIcons(t *testing.T) {
+	b := &Block{}
+	if err := MustTemplate(`{{icon "wifi"}}`, "").Render(b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.FullText != FontAwesome["wifi"] {
+		t.Errorf("default icon set: got %q", b.FullText)
+	}
+	if err := MustTemplate(`{{icon "wifi"}}`, "").Icons(Emoji).Render(b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if b.FullText != Emoji["wifi"] {
+		t.Errorf("selected icon set: got %q", b.FullText)
+	}
+	if err := MustTemplate(`{{icon "unknown"}}`, "").Render(b, nil); err == nil {
+		t.Error("unknown icon rendered")
+	}
+}
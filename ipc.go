@@ -0,0 +1,193 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ipcMagic starts every message of the i3 IPC protocol.
+const ipcMagic = "i3-ipc"
+
+// i3 IPC message and event types.
+const (
+	ipcSubscribe     uint32 = 2
+	ipcEventMask     uint32 = 1 << 31
+	ipcShutdownEvent uint32 = ipcEventMask | 6
+)
+
+// ipcRetryInterval is the delay between connection attempts
+// while the window manager restarts.
+const ipcRetryInterval = 100 * time.Millisecond
+
+// IPCSocketPath returns the path of the IPC socket of the running
+// window manager. The path is taken from $SWAYSOCK or $I3SOCK
+// and falls back to asking i3 for it.
+func IPCSocketPath() (string, error) {
+	for _, env := range []string{"SWAYSOCK", "I3SOCK"} {
+		if path := os.Getenv(env); path != "" {
+			return path, nil
+		}
+	}
+	out, err := exec.Command("i3", "--get-socketpath").Output()
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get i3 socket path")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// WatchRestarts subscribes to shutdown events of i3 or sway on the
+// IPC socket at socketPath. If socketPath is empty, IPCSocketPath is used.
+// Once the window manager restarts, WatchRestarts waits for the IPC socket
+// to come back and calls connect to obtain the writer of the new bar and
+// optionally the reader of its click events. The stream is reconnected
+// to them, which sends the protocol header again and replays the last
+// status line, see Reconnect. The writer of the previous bar is never
+// written to again, since it either belongs to a bar which is gone or
+// is in the middle of the infinite json array.
+//
+// WatchRestarts blocks until ctx is done, the window manager exits,
+// connect fails or the stream is closed.
+func (s *Stream) WatchRestarts(ctx context.Context, socketPath string, connect func() (io.Writer, io.Reader, error)) error {
+	if connect == nil {
+		return errors.New("WatchRestarts requires a connect function")
+	}
+	if socketPath == "" {
+		path, err := IPCSocketPath()
+		if err != nil {
+			return err
+		}
+		socketPath = path
+	}
+
+	for {
+		change, err := waitShutdown(ctx, socketPath)
+		if err != nil {
+			return err
+		}
+		if change != "restart" {
+			return nil
+		}
+
+		// the socket is gone until the restart is done
+		if err := waitIPC(ctx, socketPath); err != nil {
+			return err
+		}
+		w, r, err := connect()
+		if err != nil {
+			return errors.Wrap(err, "Failed to connect to restarted bar")
+		}
+		if err := s.Reconnect(w, r); err != nil {
+			return err
+		}
+	}
+}
+
+// waitShutdown waits for the next shutdown event of the window manager
+// and returns its change, e.g. "restart" or "exit".
+func waitShutdown(ctx context.Context, socketPath string) (string, error) {
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to connect to ipc socket")
+	}
+	defer conn.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	if err := writeIPC(conn, ipcSubscribe, []byte(`["shutdown"]`)); err != nil {
+		return "", err
+	}
+	for {
+		typ, payload, err := readIPC(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return "", err
+		}
+		switch typ {
+		case ipcSubscribe:
+			var reply struct {
+				Success bool `json:"success"`
+			}
+			if err := json.Unmarshal(payload, &reply); err != nil {
+				return "", errors.Wrap(err, "Failed to decode ipc reply")
+			}
+			if !reply.Success {
+				return "", withKind(ErrProtocol, errors.New("subscription to shutdown events failed"))
+			}
+		case ipcShutdownEvent:
+			var event struct {
+				Change string `json:"change"`
+			}
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return "", errors.Wrap(err, "Failed to decode ipc event")
+			}
+			return event.Change, nil
+		}
+	}
+}
+
+// waitIPC waits until the ipc socket at socketPath accepts connections again.
+func waitIPC(ctx context.Context, socketPath string) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ipcRetryInterval):
+		}
+		if conn, err := net.Dial("unix", socketPath); err == nil {
+			return conn.Close()
+		}
+	}
+}
+
+// writeIPC sends a single message of the i3 IPC protocol.
+// i3 uses the native byte order, which is little endian on all
+// platforms supported by i3 and sway in practice.
+func writeIPC(w io.Writer, typ uint32, payload []byte) error {
+	var buf bytes.Buffer
+	buf.WriteString(ipcMagic)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(payload)))
+	binary.Write(&buf, binary.LittleEndian, typ)
+	buf.Write(payload)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "Failed to write ipc message")
+	}
+	return nil
+}
+
+// readIPC reads a single message or event of the i3 IPC protocol.
+func readIPC(r io.Reader) (uint32, []byte, error) {
+	header := make([]byte, len(ipcMagic)+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, errors.Wrap(err, "Failed to read ipc message")
+	}
+	if string(header[:len(ipcMagic)]) != ipcMagic {
+		return 0, nil, withKind(ErrProtocol, errors.Errorf("invalid ipc magic: %q", header[:len(ipcMagic)]))
+	}
+	size := binary.LittleEndian.Uint32(header[len(ipcMagic):])
+	typ := binary.LittleEndian.Uint32(header[len(ipcMagic)+4:])
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, errors.Wrap(err, "Failed to read ipc message")
+	}
+	return typ, payload, nil
+}
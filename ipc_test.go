@@ -0,0 +1,90 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeIPC serves the i3 IPC socket at path and sends one shutdown event
+// with the given change to every subscriber, in order.
+// Connections which do not subscribe, e.g. to probe the socket, are ignored.
+func fakeIPC(t *testing.T, path string, changes ...string) {
+	t.Helper()
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for len(changes) > 0 {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			typ, _, err := readIPC(conn)
+			if err != nil || typ != ipcSubscribe {
+				conn.Close()
+				continue
+			}
+			writeIPC(conn, ipcSubscribe, []byte(`{"success":true}`))
+			writeIPC(conn, ipcShutdownEvent, []byte(`{"change":"`+changes[0]+`"}`))
+			changes = changes[1:]
+			conn.Close()
+		}
+	}()
+}
+
+func TestWatchRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ipc.sock")
+	fakeIPC(t, path, "restart", "exit")
+
+	var old bytes.Buffer
+	s, err := NewStream(&old, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+	sent := old.String()
+
+	var restarted bytes.Buffer
+	connects := 0
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = s.WatchRestarts(ctx, path, func() (io.Writer, io.Reader, error) {
+		connects++
+		return &restarted, nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if connects != 1 {
+		t.Errorf("connect called %d times, want 1", connects)
+	}
+	if got := old.String(); got != sent {
+		t.Errorf("previous bar got written to after restart: %q", got[len(sent):])
+	}
+	if got, want := restarted.String(), "{\"version\":1}\n[[{\"full_text\":\"a\"}]\n"; got != want {
+		t.Errorf("restarted bar got %q, want %q", got, want)
+	}
+}
+
+func TestWatchRestartsWithoutConnect(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.WatchRestarts(context.Background(), "unused", nil); err == nil {
+		t.Error("WatchRestarts without connect function succeeded")
+	}
+}
@@ -0,0 +1,43 @@
+package i3bar
+
+// Names of the blocks created by the layout helpers.
+const (
+	SpacerName    = "spacer"
+	LabelName     = "label"
+	SeparatorName = "separator"
+)
+
+// Spacer creates a block which adds pixels of blank space to the StatusLine.
+// As i3bar does not render blocks without text, the spacer is
+// at least as wide as a single space.
+func Spacer(pixels int) *Block {
+	return &Block{
+		Name:      SpacerName,
+		FullText:  " ",
+		MinWidth:  MinWidthPixels(pixels),
+		Separator: Bool(false),
+	}
+}
+
+// Label creates a static text block which is not followed by a separator,
+// e.g. to describe the next block.
+func Label(text string) *Block {
+	return &Block{
+		Name:      LabelName,
+		FullText:  text,
+		Separator: Bool(false),
+	}
+}
+
+// StaticSeparator creates a block drawing text as separator,
+// e.g. "|" or a powerline glyph, instead of i3bar's separator line.
+// Use Separator: Bool(false) on the preceding block to avoid
+// drawing both separators.
+func StaticSeparator(text string) *Block {
+	return &Block{
+		Name:      SeparatorName,
+		FullText:  text,
+		Align:     Center,
+		Separator: Bool(false),
+	}
+}
@@ -0,0 +1,29 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayoutHelpers(t *testing.T) {
+	tests := []struct {
+		name  string
+		block *Block
+		want  *Block
+	}{
+		{"spacer", Spacer(10), &Block{Name: SpacerName, FullText: " ", MinWidth: MinWidthPixels(10), Separator: Bool(false)}},
+		{"label", Label("CPU"), &Block{Name: LabelName, FullText: "CPU", Separator: Bool(false)}},
+		{"separator", StaticSeparator("|"), &Block{Name: SeparatorName, FullText: "|", Align: Center, Separator: Bool(false)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.block, tt.want) {
+				t.Errorf("got %+v, want %+v", tt.block, tt.want)
+			}
+			// i3bar does not render blocks without text
+			if err := tt.block.Validate(); err != nil {
+				t.Errorf("invalid block: %v", err)
+			}
+		})
+	}
+}
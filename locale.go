@@ -0,0 +1,187 @@
+package i3bar
+
+import (
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// dateLayouts maps languages and regions to their numeric date layout.
+// Regions take precedence over languages.
+var dateLayouts = map[string]string{
+	"en":    "02/01/2006",
+	"en-US": "01/02/2006",
+	"en-CA": "2006-01-02",
+	"de":    "02.01.2006",
+	"fr":    "02/01/2006",
+	"es":    "02/01/2006",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "02-01-2006",
+	"pl":    "02.01.2006",
+	"ru":    "02.01.2006",
+	"sv":    "2006-01-02",
+	"da":    "02.01.2006",
+	"fi":    "2.1.2006",
+	"nb":    "02.01.2006",
+	"cs":    "2. 1. 2006",
+	"ja":    "2006/01/02",
+	"zh":    "2006/1/2",
+	"ko":    "2006. 1. 2.",
+}
+
+// Locale formats numbers and dates according to the conventions of a language.
+type Locale struct {
+	tag     language.Tag
+	printer *message.Printer
+	date    string
+	clock   string
+}
+
+// NewLocale creates a Locale from a BCP 47 language tag like "de-DE"
+// or a POSIX locale name like "de_DE.UTF-8".
+func NewLocale(name string) (*Locale, error) {
+	// strip encoding and modifier of POSIX locale names
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.Replace(name, "_", "-", -1)
+	if name == "" || name == "C" || name == "POSIX" {
+		name = "en-US"
+	}
+
+	tag, err := language.Parse(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid locale %s", name)
+	}
+
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	date, ok := dateLayouts[base.String()+"-"+region.String()]
+	if !ok {
+		if date, ok = dateLayouts[base.String()]; !ok {
+			date = "2006-01-02"
+		}
+	}
+
+	clock := "15:04"
+	if region.String() == "US" {
+		clock = "3:04 PM"
+	}
+
+	return &Locale{
+		tag:     tag,
+		printer: message.NewPrinter(tag),
+		date:    date,
+		clock:   clock,
+	}, nil
+}
+
+// SystemLocale returns the Locale configured by the environment.
+// Numbers are formatted according to LC_ALL, LC_NUMERIC or LANG and
+// dates according to LC_ALL, LC_TIME or LANG, whichever is set first.
+// en-US is used as fallback.
+func SystemLocale() *Locale {
+	l := envLocale("LC_ALL", "LC_NUMERIC", "LANG")
+	t := envLocale("LC_ALL", "LC_TIME", "LANG")
+	l.date, l.clock = t.date, t.clock
+	return l
+}
+
+// envLocale returns the Locale of the first of the environment
+// variables set to a valid locale or en-US.
+func envLocale(envs ...string) *Locale {
+	for _, env := range envs {
+		if name := os.Getenv(env); name != "" {
+			if l, err := NewLocale(name); err == nil {
+				return l
+			}
+		}
+	}
+	l, _ := NewLocale("en-US")
+	return l
+}
+
+// String returns the language tag of the Locale.
+func (l *Locale) String() string {
+	return l.tag.String()
+}
+
+// Number formats an integer with the digit grouping of the Locale,
+// e.g. 1234567 as "1,234,567" or "1.234.567".
+func (l *Locale) Number(n int64) string {
+	return l.printer.Sprintf("%d", n)
+}
+
+// Decimal formats a number with prec decimal places using
+// the digit grouping and decimal separator of the Locale.
+func (l *Locale) Decimal(f float64, prec int) string {
+	return l.printer.Sprintf("%.*f", prec, f)
+}
+
+// Date formats the date of t using the numeric date layout of the Locale.
+func (l *Locale) Date(t time.Time) string {
+	return t.Format(l.date)
+}
+
+// DateTime formats the date and time of t using the numeric date layout
+// of the Locale and a 24-hour clock, or a 12-hour clock for en-US.
+func (l *Locale) DateTime(t time.Time) string {
+	return t.Format(l.date + " " + l.clock)
+}
+
+// TemplateFuncs returns template functions formatting with this Locale:
+//
+//	number n       formats an integer, e.g. number 1234567
+//	decimal n p    formats a number with p decimal places
+//	date t         formats the date of a time.Time
+//	datetime t     formats the date and time of a time.Time
+//
+// Use Template.Funcs to add them to a Template.
+func (l *Locale) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"number": func(n interface{}) (string, error) {
+			v, err := toFloat(n)
+			if err != nil {
+				return "", err
+			}
+			return l.Number(int64(v)), nil
+		},
+		"decimal": func(n interface{}, prec int) (string, error) {
+			v, err := toFloat(n)
+			if err != nil {
+				return "", err
+			}
+			return l.Decimal(v, prec), nil
+		},
+		"date":     l.Date,
+		"datetime": l.DateTime,
+	}
+}
+
+// SetLocale sets the Locale of the bar returned by Locale.
+// This function is thread safe.
+func (s *Stream) SetLocale(l *Locale) {
+	s.mwMux.Lock()
+	defer s.mwMux.Unlock()
+	s.locale = l
+}
+
+// Locale returns the Locale of the bar set by SetLocale
+// or the SystemLocale if none has been set.
+// Blocks may use a different Locale created by NewLocale.
+// This function is thread safe.
+func (s *Stream) Locale() *Locale {
+	s.mwMux.RLock()
+	l := s.locale
+	s.mwMux.RUnlock()
+	if l == nil {
+		return SystemLocale()
+	}
+	return l
+}
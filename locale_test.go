@@ -0,0 +1,49 @@
+package i3bar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSystemLocale(t *testing.T) {
+	at := time.Date(2024, 3, 7, 15, 4, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		env      map[string]string
+		number   string
+		datetime string
+	}{
+		{"fallback", nil, "1,234,567", "03/07/2024 3:04 PM"},
+		{"lang", map[string]string{"LANG": "de_DE.UTF-8"}, "1.234.567", "07.03.2024 15:04"},
+		{
+			"numeric and time",
+			map[string]string{"LANG": "en_US.UTF-8", "LC_NUMERIC": "de_DE.UTF-8", "LC_TIME": "sv_SE.UTF-8"},
+			"1.234.567", "2024-03-07 15:04",
+		},
+		{
+			"time only",
+			map[string]string{"LANG": "de_DE.UTF-8", "LC_TIME": "en_US.UTF-8"},
+			"1.234.567", "03/07/2024 3:04 PM",
+		},
+		{
+			"all",
+			map[string]string{"LC_ALL": "en_US.UTF-8", "LC_NUMERIC": "de_DE.UTF-8", "LC_TIME": "de_DE.UTF-8"},
+			"1,234,567", "03/07/2024 3:04 PM",
+		},
+		{"invalid", map[string]string{"LC_TIME": "?", "LANG": "de_DE"}, "1.234.567", "07.03.2024 15:04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LC_TIME", "LANG"} {
+				t.Setenv(env, tt.env[env])
+			}
+			l := SystemLocale()
+			if got := l.Number(1234567); got != tt.number {
+				t.Errorf("Number: got %q, want %q", got, tt.number)
+			}
+			if got := l.DateTime(at); got != tt.datetime {
+				t.Errorf("DateTime: got %q, want %q", got, tt.datetime)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package i3bar
+
+import (
+	"strings"
+	"sync"
+)
+
+// Marquee scrolls text too long for a window of fixed width through it
+// across successive updates, e.g. for media titles or window names.
+// The text is held at its start and end for Pause updates, before it
+// starts scrolling or jumps back to its start.
+type Marquee struct {
+	// Width of the window in graphemes.
+	Width int
+
+	// Step is the number of graphemes scrolled per update. Defaults to 1.
+	Step int
+
+	// Pause is the number of additional updates
+	// the text is held at its start and end.
+	Pause int
+
+	mux       sync.Mutex
+	text      string
+	graphemes []string
+	offset    int
+	held      int
+}
+
+// NewMarquee creates a Marquee for a window of width graphemes
+// scrolling by one grapheme per update.
+func NewMarquee(width int) *Marquee {
+	return &Marquee{Width: width, Step: 1}
+}
+
+// Next returns the visible part of text and scrolls for the next update.
+// Text fitting into the window is returned as is. Scrolling restarts
+// at the start of text whenever text changes.
+func (m *Marquee) Next(text string) string {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if text != m.text || m.graphemes == nil {
+		m.text = text
+		m.graphemes = Graphemes(text)
+		m.offset, m.held = 0, 0
+	}
+	if m.Width <= 0 || len(m.graphemes) <= m.Width {
+		return text
+	}
+
+	last := len(m.graphemes) - m.Width
+	if m.offset > last {
+		// Width got increased since the last update
+		m.offset = last
+	}
+	visible := strings.Join(m.graphemes[m.offset:m.offset+m.Width], "")
+
+	step := m.Step
+	if step <= 0 {
+		step = 1
+	}
+	switch {
+	case (m.offset == 0 || m.offset == last) && m.held < m.Pause:
+		m.held++
+	case m.offset == last:
+		m.offset, m.held = 0, 0
+	default:
+		m.offset, m.held = m.offset+step, 0
+		if m.offset > last {
+			m.offset = last
+		}
+	}
+	return visible
+}
+
+// Reset restarts scrolling at the start of the text.
+func (m *Marquee) Reset() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.offset, m.held = 0, 0
+}
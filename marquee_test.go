@@ -0,0 +1,58 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarquee(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      *Marquee
+		texts  []string
+		frames []string
+	}{
+		{"fits", NewMarquee(5), []string{"abc", "abc"}, []string{"abc", "abc"}},
+		{"no width", NewMarquee(0), []string{"abcde"}, []string{"abcde"}},
+		{"scroll", NewMarquee(3), []string{"abcde", "abcde", "abcde", "abcde"}, []string{"abc", "bcd", "cde", "abc"}},
+		{
+			name:   "pause",
+			m:      &Marquee{Width: 3, Step: 1, Pause: 1},
+			texts:  []string{"abcde", "abcde", "abcde", "abcde", "abcde", "abcde"},
+			frames: []string{"abc", "abc", "bcd", "cde", "cde", "abc"},
+		},
+		// the last step stops at the end of the text
+		{"step", &Marquee{Width: 2, Step: 2}, []string{"abcde", "abcde", "abcde", "abcde"}, []string{"ab", "cd", "de", "ab"}},
+		{"default step", &Marquee{Width: 3}, []string{"abcd", "abcd"}, []string{"abc", "bcd"}},
+		{"text change", NewMarquee(3), []string{"abcde", "abcde", "vwxyz"}, []string{"abc", "bcd", "vwx"}},
+		{"graphemes", NewMarquee(2), []string{"🇩🇪🇫🇷🇮🇹", "🇩🇪🇫🇷🇮🇹"}, []string{"🇩🇪🇫🇷", "🇫🇷🇮🇹"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var frames []string
+			for _, text := range tt.texts {
+				frames = append(frames, tt.m.Next(text))
+			}
+			if !reflect.DeepEqual(frames, tt.frames) {
+				t.Errorf("got frames %q, want %q", frames, tt.frames)
+			}
+		})
+	}
+}
+
+func TestMarqueeReset(t *testing.T) {
+	m := NewMarquee(3)
+	m.Next("abcde")
+	m.Next("abcde")
+	m.Reset()
+	if got := m.Next("abcde"); got != "abc" {
+		t.Errorf("got %q after Reset, want abc", got)
+	}
+
+	// a wider window moves the offset back into the text
+	m.Next("abcde")
+	m.Width = 4
+	if got := m.Next("abcde"); got != "bcde" {
+		t.Errorf("got %q after widening, want bcde", got)
+	}
+}
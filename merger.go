@@ -0,0 +1,212 @@
+package i3bar
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Source produces status lines for a Merger.
+type Source interface {
+	// Run sends status lines to updates until ctx is done
+	// or the source has no more status lines.
+	// Run must return once ctx is done.
+	Run(ctx context.Context, updates chan<- StatusLine) error
+}
+
+// SourceFunc adapts a function to a Source, e.g. for local modules.
+type SourceFunc func(ctx context.Context, updates chan<- StatusLine) error
+
+// Run calls f.
+func (f SourceFunc) Run(ctx context.Context, updates chan<- StatusLine) error {
+	return f(ctx, updates)
+}
+
+// ProxySource uses the status lines of p as Source.
+// Once ctx is done, p is closed and so is the reader passed to
+// NewProxy if it is an io.Closer, so that reading is interrupted.
+func ProxySource(p *Proxy) Source {
+	return SourceFunc(func(ctx context.Context, updates chan<- StatusLine) error {
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			select {
+			case <-ctx.Done():
+				p.Close()
+				if c, ok := p.r.(io.Closer); ok {
+					c.Close()
+				}
+			case <-stop:
+			}
+		}()
+
+		for {
+			line, err := p.Next()
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			select {
+			case updates <- line:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	})
+}
+
+// Merger composes the latest status lines of several sources
+// into a single StatusLine.
+type Merger struct {
+	mux     sync.Mutex
+	names   []string
+	sources map[string]Source
+	latest  map[string]StatusLine
+}
+
+// NewMerger creates an empty Merger.
+func NewMerger() *Merger {
+	return &Merger{
+		sources: map[string]Source{},
+		latest:  map[string]StatusLine{},
+	}
+}
+
+// Add adds src identified by name. Sources are composed
+// in the order they are added unless changed by SetOrder.
+// Adding a source with an existing name replaces the source.
+func (m *Merger) Add(name string, src Source) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if _, ok := m.sources[name]; !ok {
+		m.names = append(m.names, name)
+	}
+	m.sources[name] = src
+}
+
+// AddReader adds the i3bar protocol stream read from r as source identified by name.
+func (m *Merger) AddReader(name string, r io.Reader) error {
+	p, err := NewProxy(r)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to add source %s", name)
+	}
+	m.Add(name, ProxySource(p))
+	return nil
+}
+
+// SetOrder changes the order in which sources are composed.
+// Sources not named are composed after the named ones
+// in the order they were added.
+func (m *Merger) SetOrder(names ...string) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	seen := map[string]bool{}
+	order := make([]string, 0, len(m.names))
+	for _, name := range names {
+		if _, ok := m.sources[name]; ok && !seen[name] {
+			order = append(order, name)
+			seen[name] = true
+		}
+	}
+	for _, name := range m.names {
+		if !seen[name] {
+			order = append(order, name)
+		}
+	}
+	m.names = order
+}
+
+// Line composes the latest status lines of all sources.
+func (m *Merger) Line() StatusLine {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	var line StatusLine
+	for _, name := range m.names {
+		line = append(line, m.latest[name]...)
+	}
+	return line
+}
+
+// update stores the latest status line of the source identified by name.
+func (m *Merger) update(name string, line StatusLine) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.latest[name] = line
+}
+
+// Run runs all sources concurrently and sends the composed status line
+// to s whenever one of the sources sends a new status line.
+// Errors of single sources are reported on s.Errors() while the
+// remaining sources keep running.
+// Run returns once ctx is done, sending a status line failed or all
+// sources are finished. Remaining sources are stopped and waited for.
+func (m *Merger) Run(ctx context.Context, s *Stream) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	m.mux.Lock()
+	names := append([]string(nil), m.names...)
+	sources := make([]Source, len(names))
+	for i, name := range names {
+		sources[i] = m.sources[name]
+	}
+	m.mux.Unlock()
+
+	type update struct {
+		name string
+		line StatusLine
+	}
+	updates := make(chan update)
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(name string, src Source) {
+			defer wg.Done()
+			lines := make(chan StatusLine)
+			forwarded := make(chan struct{})
+			go func() {
+				defer close(forwarded)
+				for line := range lines {
+					select {
+					case updates <- update{name, line}:
+					case <-ctx.Done():
+					}
+				}
+			}()
+			if err := src.Run(ctx, lines); err != nil {
+				s.reportError(errors.Wrapf(err, "source %s failed", name))
+			}
+			close(lines)
+			<-forwarded
+		}(name, sources[i])
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	defer func() {
+		cancel()
+		<-finished
+	}()
+
+	for {
+		select {
+		case u := <-updates:
+			m.update(u.name, u.line)
+			if err := s.SendLine(m.Line()); err != nil {
+				return err
+			}
+		case <-finished:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
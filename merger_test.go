@@ -0,0 +1,148 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// lines returns a Source sending the status lines consisting of
+// a single block with each of the texts.
+func lines(texts ...string) Source {
+	return SourceFunc(func(ctx context.Context, updates chan<- StatusLine) error {
+		for _, text := range texts {
+			select {
+			case updates <- StatusLine{{FullText: text}}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// lastLine decodes the last status line written to out.
+func lastLine(t *testing.T, out string) []string {
+	t.Helper()
+	rows := strings.Split(strings.TrimSpace(out), "\n")
+	var line StatusLine
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(rows[len(rows)-1], ",")), &line); err != nil {
+		t.Fatalf("decoding %q: %v", rows[len(rows)-1], err)
+	}
+	return blockTexts(line)
+}
+
+// blockTexts returns the texts of the blocks of line.
+func blockTexts(line StatusLine) []string {
+	texts := make([]string, len(line))
+	for i, b := range line {
+		texts[i] = b.FullText
+	}
+	return texts
+}
+
+func TestMergerOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order []string
+		want  string
+	}{
+		{"added", nil, "a2 b1 c3"},
+		{"set", []string{"c", "a", "b"}, "c3 a2 b1"},
+		{"partial", []string{"b"}, "b1 a2 c3"},
+		{"unknown", []string{"x", "c"}, "c3 a2 b1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMerger()
+			m.Add("a", lines("a1", "a2"))
+			m.Add("b", lines("b1"))
+			m.Add("c", lines("c1", "c2", "c3"))
+			if tt.order != nil {
+				m.SetOrder(tt.order...)
+			}
+
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, false, DefaultHeader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			if err := m.Run(context.Background(), s); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(lastLine(t, out.String()), " "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if got := strings.Join(blockTexts(m.Line()), " "); got != tt.want {
+				t.Errorf("Line: got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergerCancel(t *testing.T) {
+	r, w := io.Pipe()
+	go io.WriteString(w, "{\"version\":1}\n[\n[{\"full_text\":\"proxied\"}]\n")
+
+	m := NewMerger()
+	if err := m.AddReader("proxy", r); err != nil {
+		t.Fatal(err)
+	}
+	var out lockedBuffer
+	s, err := NewStream(&out, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx, s) }()
+	waitFor(t, "proxied line", func() bool { return strings.Contains(out.String(), "proxied") })
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	// the reader of the proxy got closed once Run returned
+	if _, err := io.WriteString(w, "[]\n"); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("write to proxied stream: got %v, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestMergerSendError(t *testing.T) {
+	m := NewMerger()
+	blocked := make(chan struct{})
+	m.Add("a", lines("a1"))
+	m.Add("b", SourceFunc(func(ctx context.Context, updates chan<- StatusLine) error {
+		<-ctx.Done()
+		close(blocked)
+		return nil
+	}))
+
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if err := m.Run(context.Background(), s); !errors.Is(err, ErrClosed) {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+	select {
+	case <-blocked:
+	default:
+		t.Error("remaining source still running after Run returned")
+	}
+}
@@ -0,0 +1,52 @@
+package i3bar
+
+// Middleware transforms a StatusLine before it is sent.
+// A Middleware may modify the passed StatusLine and its blocks,
+// as it always receives a copy of the status line passed to SendLine.
+type Middleware func(StatusLine) StatusLine
+
+// Use appends mw to the middlewares applied to every status line
+// before it is sent. Middlewares are applied in the order they are added.
+// This function is thread safe.
+func (s *Stream) Use(mw ...Middleware) {
+	s.mwMux.Lock()
+	defer s.mwMux.Unlock()
+	// copy on write, so that prepare can use the slice without holding the lock
+	middlewares := make([]Middleware, 0, len(s.middlewares)+len(mw))
+	middlewares = append(middlewares, s.middlewares...)
+	s.middlewares = append(middlewares, mw...)
+}
+
+// ClickMiddleware wraps the handling of click events, e.g. for logging,
+// filtering or metrics. It passes the event, possibly modified, to next
+// or drops it by not calling next.
+type ClickMiddleware func(ev ClickEvent, next func(ClickEvent))
+
+// UseClick appends mw to the middlewares wrapping the handling of every
+// click event, including click handlers and the Events channel.
+// The middleware added first is the outermost one.
+// This function is thread safe.
+func (s *Stream) UseClick(mw ...ClickMiddleware) {
+	s.rMux.Lock()
+	defer s.rMux.Unlock()
+	// copy on write, so that handleClick can use the slice without holding the lock
+	middlewares := make([]ClickMiddleware, 0, len(s.clickMiddlewares)+len(mw))
+	middlewares = append(middlewares, s.clickMiddlewares...)
+	s.clickMiddlewares = append(middlewares, mw...)
+}
+
+// handleClick passes ev through all click middlewares to final.
+func (s *Stream) handleClick(ev ClickEvent, final func(ClickEvent)) {
+	s.rMux.Lock()
+	middlewares := s.clickMiddlewares
+	s.rMux.Unlock()
+
+	next := final
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		mw, inner := middlewares[i], next
+		next = func(ev ClickEvent) {
+			mw(ev, inner)
+		}
+	}
+	next(ev)
+}
@@ -0,0 +1,196 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sentLines decodes the status lines of the closed stream written to out.
+func sentLines(t *testing.T, out *bytes.Buffer) []StatusLine {
+	t.Helper()
+	d := json.NewDecoder(out)
+	var h Header
+	if err := d.Decode(&h); err != nil {
+		t.Fatal(err)
+	}
+	var lines []StatusLine
+	if err := d.Decode(&lines); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+// suffix returns a middleware appending s to the full text of all blocks.
+func suffix(s string) Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			b.FullText += s
+		}
+		return line
+	}
+}
+
+func TestUse(t *testing.T) {
+	drop := func(line StatusLine) StatusLine {
+		line.Remove("b", "")
+		return line
+	}
+
+	tests := []struct {
+		name        string
+		middlewares [][]Middleware
+		want        []string
+	}{
+		{"none", nil, []string{"a", "b"}},
+		{"single", [][]Middleware{{suffix("1")}}, []string{"a1", "b1"}},
+		{"in order", [][]Middleware{{suffix("1"), suffix("2")}}, []string{"a12", "b12"}},
+		{"multiple calls", [][]Middleware{{suffix("1")}, {suffix("2")}}, []string{"a12", "b12"}},
+		{"remove blocks", [][]Middleware{{drop, suffix("1")}}, []string{"a1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, false, DefaultHeader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, mw := range tt.middlewares {
+				s.Use(mw...)
+			}
+			line := StatusLine{{Name: "a", FullText: "a"}, {Name: "b", FullText: "b"}}
+			if err := s.SendLine(line); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the status line of the caller is never modified
+			if line[0].FullText != "a" || line[1].FullText != "b" {
+				t.Errorf("middleware modified the status line of the caller: %q %q", line[0].FullText, line[1].FullText)
+			}
+			lines := sentLines(t, &out)
+			if len(lines) != 1 {
+				t.Fatalf("sent %d status lines, want 1", len(lines))
+			}
+			var got []string
+			for _, b := range lines[0] {
+				got = append(got, b.FullText)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUseClick(t *testing.T) {
+	// trace returns a middleware recording its name before and after next
+	trace := func(calls *[]string, name string) ClickMiddleware {
+		return func(ev ClickEvent, next func(ClickEvent)) {
+			*calls = append(*calls, name)
+			next(ev)
+			*calls = append(*calls, "/"+name)
+		}
+	}
+	drop := func(ClickEvent, func(ClickEvent)) {}
+	swap := func(ev ClickEvent, next func(ClickEvent)) {
+		ev.Button = RightClick
+		next(ev)
+	}
+
+	tests := []struct {
+		name        string
+		middlewares func(calls *[]string) [][]ClickMiddleware
+		want        []string
+		button      Button
+	}{
+		{
+			name:        "none",
+			middlewares: func(*[]string) [][]ClickMiddleware { return nil },
+			want:        []string{"final"},
+			button:      LeftClick,
+		},
+		{
+			name: "first is outermost",
+			middlewares: func(calls *[]string) [][]ClickMiddleware {
+				return [][]ClickMiddleware{{trace(calls, "a"), trace(calls, "b")}}
+			},
+			want:   []string{"a", "b", "final", "/b", "/a"},
+			button: LeftClick,
+		},
+		{
+			name: "multiple calls",
+			middlewares: func(calls *[]string) [][]ClickMiddleware {
+				return [][]ClickMiddleware{{trace(calls, "a")}, {trace(calls, "b")}}
+			},
+			want:   []string{"a", "b", "final", "/b", "/a"},
+			button: LeftClick,
+		},
+		{
+			name: "drop",
+			middlewares: func(calls *[]string) [][]ClickMiddleware {
+				return [][]ClickMiddleware{{trace(calls, "a"), drop, trace(calls, "b")}}
+			},
+			want: []string{"a", "/a"},
+		},
+		{
+			name: "modify",
+			middlewares: func(calls *[]string) [][]ClickMiddleware {
+				return [][]ClickMiddleware{{swap}}
+			},
+			want:   []string{"final"},
+			button: RightClick,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Stream
+			var calls []string
+			for _, mw := range tt.middlewares(&calls) {
+				s.UseClick(mw...)
+			}
+			var button Button
+			s.handleClick(ClickEvent{Button: LeftClick}, func(ev ClickEvent) {
+				calls = append(calls, "final")
+				button = ev.Button
+			})
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("got calls %q, want %q", calls, tt.want)
+			}
+			if button != tt.button {
+				t.Errorf("got button %v, want %v", button, tt.button)
+			}
+		})
+	}
+}
+
+func TestUseClickEvents(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	// drop all events of block b
+	s.UseClick(func(ev ClickEvent, next func(ClickEvent)) {
+		if ev.Name != "b" {
+			next(ev)
+		}
+	})
+
+	io.WriteString(w, "[\n"+`{"name":"b","button":1}`+"\n"+`,{"name":"a","button":1}`+"\n")
+	select {
+	case ev := <-s.Events():
+		if ev.Name != "a" {
+			t.Errorf("got event of %q, want a", ev.Name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+}
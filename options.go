@@ -0,0 +1,228 @@
+package i3bar
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// Option configures optional behaviour of a Stream.
+type Option func(*Stream)
+
+// WithDedup enables suppression of duplicate status lines.
+// A status line is skipped if its encoding equals the
+// encoding of the last status line sent.
+func WithDedup() Option {
+	return func(s *Stream) {
+		s.dedup = true
+	}
+}
+
+// WithMinInterval enforces a minimum time of d between two status lines.
+// Status lines sent faster are coalesced, so that only the latest
+// one is sent once d has passed.
+func WithMinInterval(d time.Duration) Option {
+	return func(s *Stream) {
+		s.minInterval = d
+	}
+}
+
+// WithAsync decouples SendLine from writing to the stream.
+// SendLine only stores the latest status line and a single writer
+// sends it at most n times per second, always emitting the most recent state.
+func WithAsync(n int) Option {
+	return func(s *Stream) {
+		if n <= 0 {
+			return
+		}
+		s.asyncInterval = time.Second / time.Duration(n)
+		s.notify = make(chan struct{}, 1)
+	}
+}
+
+// Target is the bar implementation reading the stream.
+type Target int
+
+const (
+	// I3bar does not support colors with alpha channel.
+	I3bar Target = iota
+	// Swaybar supports colors with alpha channel.
+	Swaybar
+)
+
+// WithTarget declares the bar implementation reading the stream.
+// Alpha channels of colors are stripped unless the target is Swaybar.
+// The default target is I3bar.
+func WithTarget(t Target) Option {
+	return func(s *Stream) {
+		s.target = t
+	}
+}
+
+// WithValidation validates every block before it is sent.
+// Invalid status lines are rejected with an InvalidLineError.
+func WithValidation() Option {
+	return func(s *Stream) {
+		s.validate = true
+	}
+}
+
+// WithStrict validates every block before it is sent like WithValidation,
+// but additionally rejects constructs which i3bar silently ignores or
+// renders wrong instead of fixing them up: colors with alpha channel
+// unless the target is Swaybar and Extra keys shadowed by regular fields.
+// Invalid status lines are rejected with an InvalidLineError.
+func WithStrict() Option {
+	return func(s *Stream) {
+		s.strict = true
+	}
+}
+
+// WithPlainText writes every status line sent to i3bar to w as well,
+// rendered as a single line of plain text by PlainText with the
+// icon set of the stream. This is suitable for screen readers,
+// logs or other tools.
+func WithPlainText(w io.Writer) Option {
+	return func(s *Stream) {
+		s.plain = w
+	}
+}
+
+// WithIcons selects the icon set of the stream returned by Stream.Icons.
+// The default icon set is FontAwesome.
+func WithIcons(set IconSet) Option {
+	return func(s *Stream) {
+		s.icons = set
+	}
+}
+
+// WithFarewell sets the status line sent by Shutdown right before the
+// infinite json array is closed, e.g. to show that the status command exited.
+func WithFarewell(line StatusLine) Option {
+	return func(s *Stream) {
+		s.farewell = line.Clone()
+	}
+}
+
+// WithClickHandler registers fn to be called for every click event.
+// Click events are enabled in the header automatically, so the reader
+// passed to NewStream must not be nil.
+// If any click handler is registered, click events are delivered to
+// the handlers instead of the Events channel. Handlers are called one
+// after another in the order they were registered, from a single
+// goroutine unless WithAsyncClicks is used.
+func WithClickHandler(fn func(ev ClickEvent)) Option {
+	return func(s *Stream) {
+		s.header.ClickEvents = true
+		s.clickHandlers = append(s.clickHandlers, fn)
+	}
+}
+
+// WithClickEvents enables click events in the header, so that click
+// handlers can be registered by Stream.OnClick, Stream.Bind and Block.OnClick
+// once the stream is set up. The reader passed to NewStream must not be nil.
+func WithClickEvents() Option {
+	return func(s *Stream) {
+		s.header.ClickEvents = true
+		s.clickReader = true
+	}
+}
+
+// AllowUnknownVersion accepts header versions newer than ProtocolVersion
+// for experimentation. By default such headers are rejected, because
+// i3bar silently falls back to the plain text protocol for them.
+func AllowUnknownVersion() Option {
+	return func(s *Stream) {
+		s.allowUnknownVersion = true
+	}
+}
+
+// WithRefreshSignal calls Refresh whenever sig is received,
+// commonly syscall.SIGUSR1.
+func WithRefreshSignal(sig os.Signal) Option {
+	return func(s *Stream) {
+		s.refreshSignal = sig
+	}
+}
+
+// WithScrollDebounce coalesces consecutive scroll events with the same
+// button on the same block within window into a single event.
+// The number of coalesced events is reported in ClickEvent.Delta.
+// Other click events are delivered after pending scroll events.
+func WithScrollDebounce(window time.Duration) Option {
+	return func(s *Stream) {
+		s.scrollWindow = window
+	}
+}
+
+// WithDoubleClick holds back clicks for window to detect double clicks,
+// since i3bar only reports individual clicks. A second click with the
+// same button on the same block within window is delivered as a single
+// event with ClickEvent.Double set, otherwise the held click is delivered
+// once window has passed. If window is not positive,
+// DefaultDoubleClickWindow is used.
+// Scroll events and clicks on other blocks are delivered after the held click.
+func WithDoubleClick(window time.Duration) Option {
+	return func(s *Stream) {
+		if window <= 0 {
+			window = DefaultDoubleClickWindow
+		}
+		s.doubleClickWindow = window
+	}
+}
+
+// WithClickReplay keeps up to n click events which arrived before a
+// handler for their block was registered by Stream.OnClick or Block.OnClick.
+// Once the handler is registered, the events are dispatched to it in order
+// with live events, unless they are older than maxAge. A maxAge of 0 keeps
+// events until they are replayed or dropped for newer ones.
+// Only events no one else consumed are kept: handlers registered by
+// WithClickHandler receive all events as usual and so does the Events
+// channel once Stream.Events has been called. Events are only kept
+// while none of them is registered.
+func WithClickReplay(n int, maxAge time.Duration) Option {
+	return func(s *Stream) {
+		if n <= 0 {
+			return
+		}
+		s.replaySize = n
+		s.replayAge = maxAge
+		s.replayNotify = make(chan struct{}, 1)
+	}
+}
+
+// WithAsyncClicks runs click handlers on goroutines, so that slow handlers
+// do not delay click events on other blocks. Click events on the same
+// block are still handled one after another in the order they arrived.
+// If limit is positive, at most limit handlers run at the same time.
+func WithAsyncClicks(limit int) Option {
+	return func(s *Stream) {
+		s.asyncClicks = true
+		if limit > 0 {
+			s.clickSlots = make(chan struct{}, limit)
+		}
+	}
+}
+
+// WithPangoEscape escapes stray &, < and > in the texts of all blocks
+// using pango markup before they are sent, while valid pango tags and
+// character references are kept. This prevents user supplied text from
+// breaking the markup. Use PangoEscape to escape text explicitly.
+func WithPangoEscape() Option {
+	return func(s *Stream) {
+		s.pangoEscape = true
+	}
+}
+
+// WithBlink makes urgent blocks blink every interval while they stay urgent.
+// In the off phase urgent blocks are sent as not urgent with color and
+// background, so that the urgent colors of the bar and the colors of the
+// block alternate. Empty colors keep the colors of the block.
+// Blink frames are subject to WithMinInterval like any status line.
+func WithBlink(interval time.Duration, color, background Color) Option {
+	return func(s *Stream) {
+		s.blinkInterval = interval
+		s.blinkColor = color
+		s.blinkBackground = background
+	}
+}
@@ -0,0 +1,146 @@
+package i3bar
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Filter decides whether a block is sent to an output.
+type Filter func(b *Block) bool
+
+// Only returns a Filter which accepts blocks with one of the given names.
+func Only(names ...string) Filter {
+	set := nameSet(names)
+	return func(b *Block) bool {
+		return set[b.Name]
+	}
+}
+
+// Except returns a Filter which accepts all blocks except the ones with one of the given names.
+func Except(names ...string) Filter {
+	set := nameSet(names)
+	return func(b *Block) bool {
+		return !set[b.Name]
+	}
+}
+
+// nameSet creates a lookup set of names.
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// output is a single Stream managed by an OutputManager.
+type output struct {
+	name   string
+	stream *Stream
+	filter Filter
+}
+
+// OutputManager drives several Streams, e.g. one per monitor or
+// bar instance, from a single source of status lines.
+type OutputManager struct {
+	mux     sync.RWMutex
+	outputs []*output
+}
+
+// NewOutputManager creates an OutputManager without any outputs.
+func NewOutputManager() *OutputManager {
+	return &OutputManager{}
+}
+
+// Add adds the Stream s as output identified by name.
+// Only blocks accepted by filter are sent to s. If filter is nil,
+// all blocks are sent. An existing output with the same name is replaced.
+func (m *OutputManager) Add(name string, s *Stream, filter Filter) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	o := &output{name: name, stream: s, filter: filter}
+	for i, existing := range m.outputs {
+		if existing.name == name {
+			m.outputs[i] = o
+			return
+		}
+	}
+	m.outputs = append(m.outputs, o)
+}
+
+// Remove removes the output identified by name and returns its Stream.
+// The Stream is not closed. Remove returns nil if there is no such output.
+func (m *OutputManager) Remove(name string) *Stream {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	for i, o := range m.outputs {
+		if o.name == name {
+			m.outputs = append(m.outputs[:i], m.outputs[i+1:]...)
+			return o.stream
+		}
+	}
+	return nil
+}
+
+// Stream returns the Stream of the output identified by name
+// or nil if there is no such output.
+func (m *OutputManager) Stream(name string) *Stream {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	for _, o := range m.outputs {
+		if o.name == name {
+			return o.stream
+		}
+	}
+	return nil
+}
+
+// SendLine sends the blocks of line accepted by the filter of
+// each output to its Stream. All outputs are tried even if sending
+// to one of them fails, the first error is returned.
+// This function is thread safe.
+func (m *OutputManager) SendLine(line StatusLine) error {
+	m.mux.RLock()
+	outputs := append([]*output(nil), m.outputs...)
+	m.mux.RUnlock()
+
+	var first error
+	for _, o := range outputs {
+		if err := o.stream.SendLine(o.apply(line)); err != nil && first == nil {
+			first = errors.Wrapf(err, "output %s", o.name)
+		}
+	}
+	return first
+}
+
+// Close closes the Streams of all outputs.
+// All Streams are closed even if closing one of them fails,
+// the first error is returned.
+func (m *OutputManager) Close() error {
+	m.mux.RLock()
+	outputs := append([]*output(nil), m.outputs...)
+	m.mux.RUnlock()
+
+	var first error
+	for _, o := range outputs {
+		if err := o.stream.Close(); err != nil && first == nil {
+			first = errors.Wrapf(err, "output %s", o.name)
+		}
+	}
+	return first
+}
+
+// apply returns the blocks of line accepted by the filter of the output.
+func (o *output) apply(line StatusLine) StatusLine {
+	if o.filter == nil {
+		return line
+	}
+	filtered := make(StatusLine, 0, len(line))
+	for _, b := range line {
+		if b != nil && o.filter(b) {
+			filtered = append(filtered, b)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,103 @@
+package i3bar
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestOutputManager(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"all", nil, []string{"cpu/", "memory/", "clock/"}},
+		{"only", Only("clock", "disk"), []string{"clock/"}},
+		{"except", Except("cpu"), []string{"memory/", "clock/"}},
+		{"none", Only(), nil},
+	}
+
+	m := NewOutputManager()
+	outs := make([]*bytes.Buffer, len(tests))
+	for i, tt := range tests {
+		outs[i] = &bytes.Buffer{}
+		s, err := NewStream(outs[i], nil, false, DefaultHeader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Add(tt.name, s, tt.filter)
+	}
+	line := StatusLine{{Name: "cpu"}, {Name: "memory"}, {Name: "clock"}}
+	if err := m.SendLine(line); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(line) != 3 {
+		t.Error("filters modified the status line")
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := sentLines(t, outs[i])
+			if len(lines) != 1 {
+				t.Fatalf("sent %d status lines, want 1", len(lines))
+			}
+			if got := names(lines[0]); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputManagerOutputs(t *testing.T) {
+	newStream := func() *Stream {
+		s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	a, b, c := newStream(), newStream(), newStream()
+
+	m := NewOutputManager()
+	m.Add("a", a, nil)
+	m.Add("b", b, nil)
+	if m.Stream("a") != a || m.Stream("b") != b || m.Stream("c") != nil {
+		t.Error("Stream returned the wrong streams")
+	}
+
+	// adding an existing output replaces it
+	m.Add("a", c, nil)
+	if m.Stream("a") != c {
+		t.Error("output a not replaced")
+	}
+
+	if m.Remove("a") != c || m.Stream("a") != nil {
+		t.Error("output a not removed")
+	}
+	if m.Remove("a") != nil {
+		t.Error("removed missing output")
+	}
+
+	// all outputs are tried, even if sending to one of them fails
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	m.Add("c", c, nil)
+	err := m.SendLine(StatusLine{{FullText: "x"}})
+	if !errors.Is(err, ErrClosed) || !strings.Contains(err.Error(), "output b") {
+		t.Errorf("got %v, want ErrClosed of output b", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package i3bar
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pangoEscaper escapes the characters with special meaning in pango markup.
+var pangoEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"'", "&apos;",
+	`"`, "&quot;",
+)
+
+// pangoEntity matches character references at the start of a string.
+var pangoEntity = regexp.MustCompile(`^&(amp|lt|gt|apos|quot|#[0-9]+|#x[0-9a-fA-F]+);`)
+
+// PangoEscape escapes s for use within pango markup, so that
+// user supplied text containing &, < or > can not break the markup.
+func PangoEscape(s string) string {
+	return pangoEscaper.Replace(s)
+}
+
+// sanitizePango escapes all &, < and > in s which are not part of
+// a pango tag or character reference.
+func sanitizePango(s string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range findPangoTags(s, -1) {
+		sanitizeText(&sb, s[last:loc[0]])
+		sb.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	sanitizeText(&sb, s[last:])
+	return sb.String()
+}
+
+// sanitizeText writes text without tags to sb and escapes
+// &, < and > unless they start a character reference.
+func sanitizeText(sb *strings.Builder, text string) {
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '&' && pangoEntity.MatchString(text[i:]):
+			sb.WriteByte(c)
+		case c == '&':
+			sb.WriteString("&amp;")
+		case c == '<':
+			sb.WriteString("&lt;")
+		case c == '>':
+			sb.WriteString("&gt;")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// escapePango returns line with stray &, < and > escaped in the texts
+// of all blocks using pango markup. Affected blocks are copied, so that
+// line itself is never modified.
+func escapePango(line StatusLine) StatusLine {
+	out := make(StatusLine, len(line))
+	for i, b := range line {
+		if b == nil || b.Markup != Pango {
+			out[i] = b
+			continue
+		}
+		escaped := *b
+		escaped.FullText = sanitizePango(b.FullText)
+		escaped.ShortText = sanitizePango(b.ShortText)
+		if lazy := b.LazyText; lazy != nil {
+			escaped.LazyText = TextFunc(func() string {
+				return sanitizePango(lazy.String())
+			})
+		}
+		out[i] = &escaped
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package i3bar
+
+import "testing"
+
+func TestSanitizePango(t *testing.T) {
+	tests := []struct {
+		in, want string
+		tags     bool
+	}{
+		{"plain", "plain", false},
+		{"<b>bold</b> & more", "<b>bold</b> &amp; more", true},
+		{`<span foreground="#ff0000">1 < 2</span>`, `<span foreground="#ff0000">1 &lt; 2</span>`, true},
+		{"<script>x</script>", "&lt;script&gt;x&lt;/script&gt;", false},
+		{"a &amp; b &#x41; <br/>", "a &amp; b &#x41; &lt;br/&gt;", false},
+		{"<<b>>", "&lt;<b>&gt;", true},
+	}
+	for _, tt := range tests {
+		if got := sanitizePango(tt.in); got != tt.want {
+			t.Errorf("sanitizePango(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := hasPangoTag(tt.in); got != tt.tags {
+			t.Errorf("hasPangoTag(%q) = %v, want %v", tt.in, got, tt.tags)
+		}
+	}
+}
+
+func TestSpanHelpers(t *testing.T) {
+	if got, want := powerlineGlyph(PowerlineArrow, "#ff0000", "invalid"), `<span foreground="#ff0000">`+PowerlineArrow+`</span>`; got != want {
+		t.Errorf("powerlineGlyph: got %q, want %q", got, want)
+	}
+	if got := powerlineGlyph("<", "", ""); got != "&lt;" {
+		t.Errorf("powerlineGlyph without colors: got %q", got)
+	}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{`{{fg "#00ff00" (escape .)}}`, `<span foreground="#00ff00">&lt;ok&gt;</span>`},
+		{`{{bg "#000000" "&amp;"}}`, `<span background="#000000">&amp;</span>`},
+		// fg and bg take markup, so that spans can be nested
+		{`{{fg "#00ff00" (bg "#000000" (escape .))}}`, `<span foreground="#00ff00"><span background="#000000">&lt;ok&gt;</span></span>`},
+	}
+	for _, tt := range tests {
+		tmpl, err := NewTemplate(tt.tmpl, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := &Block{}
+		if err := tmpl.Render(b, "<ok>"); err != nil {
+			t.Fatal(err)
+		}
+		if b.FullText != tt.want {
+			t.Errorf("template %s: got %q, want %q", tt.tmpl, b.FullText, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package i3bar
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pangoTags are the tags supported by pango markup.
+var pangoTags = map[string]bool{
+	"span": true, "b": true, "big": true, "i": true, "s": true,
+	"sub": true, "sup": true, "small": true, "tt": true, "u": true,
+}
+
+// spanAttrs are the attributes supported by pango spans.
+var spanAttrs = map[string]bool{
+	"font": true, "font_desc": true, "font_family": true, "face": true,
+	"font_size": true, "size": true, "font_style": true, "style": true,
+	"font_weight": true, "weight": true, "font_variant": true, "variant": true,
+	"font_stretch": true, "stretch": true, "font_features": true,
+	"foreground": true, "fgcolor": true, "color": true,
+	"background": true, "bgcolor": true,
+	"alpha": true, "fgalpha": true, "background_alpha": true, "bgalpha": true,
+	"underline": true, "underline_color": true,
+	"overline": true, "overline_color": true,
+	"strikethrough": true, "strikethrough_color": true,
+	"rise": true, "baseline_shift": true, "font_scale": true,
+	"fallback": true, "lang": true, "letter_spacing": true,
+	"gravity": true, "gravity_hint": true, "show": true,
+	"insert_hyphens": true, "allow_breaks": true, "line_height": true,
+	"text_transform": true, "segment": true,
+}
+
+// markupTag matches a complete tag and captures whether it is closing,
+// its name, its attributes and whether it is self-closing.
+var markupTag = regexp.MustCompile(`^<(/?)([a-zA-Z_]*)((?:\s+[^\s=>/]+\s*=\s*(?:"[^"]*"|'[^']*'))*)\s*(/?)>`)
+
+// findPangoTags returns the start and end offsets of at most n complete
+// tags supported by pango markup in s, or of all if n is negative.
+func findPangoTags(s string, n int) [][]int {
+	var tags [][]int
+	for i := 0; i < len(s) && len(tags) != n; i++ {
+		if s[i] != '<' {
+			continue
+		}
+		if m := markupTag.FindStringSubmatch(s[i:]); m != nil && pangoTags[m[2]] {
+			tags = append(tags, []int{i, i + len(m[0])})
+			i += len(m[0]) - 1
+		}
+	}
+	return tags
+}
+
+// hasPangoTag reports whether s contains a tag supported by pango markup.
+func hasPangoTag(s string) bool {
+	return len(findPangoTags(s, 1)) > 0
+}
+
+// markupAttr matches a single attribute of a tag.
+var markupAttr = regexp.MustCompile(`([^\s=]+)\s*=\s*(?:"[^"]*"|'[^']*')`)
+
+// MarkupError describes a problem within pango markup.
+type MarkupError struct {
+	// Offset of the problem in bytes.
+	Offset int
+
+	// Reason describes the problem.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Reason)
+}
+
+// MarkupErrors contains all problems within pango markup.
+type MarkupErrors []*MarkupError
+
+// Error implements the error interface.
+func (e MarkupErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid pango markup: " + strings.Join(msgs, ", ")
+}
+
+// ValidatePango checks markup for unbalanced or unknown tags, unknown span
+// attributes and unescaped & and <, which make i3bar render the raw markup.
+// If markup is invalid, MarkupErrors describing all problems are returned.
+func ValidatePango(markup string) error {
+	if errs := lintPango(markup); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// lintPango collects all problems within markup.
+func lintPango(markup string) MarkupErrors {
+	var errs MarkupErrors
+	invalid := func(offset int, format string, args ...interface{}) {
+		errs = append(errs, &MarkupError{Offset: offset, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	type openTag struct {
+		name   string
+		offset int
+	}
+	var open []openTag
+
+	for i := 0; i < len(markup); i++ {
+		switch markup[i] {
+		case '&':
+			if !pangoEntity.MatchString(markup[i:]) {
+				invalid(i, "unescaped &, use &amp;")
+			}
+		case '<':
+			m := markupTag.FindStringSubmatch(markup[i:])
+			if m == nil {
+				invalid(i, "unescaped < or malformed tag, use &lt;")
+				continue
+			}
+			closing, name, attrs, selfClosing := m[1] == "/", m[2], m[3], m[4] == "/"
+			if !pangoTags[name] {
+				invalid(i, "unknown tag <%s>", name)
+			}
+			switch {
+			case closing && len(open) == 0:
+				invalid(i, "unexpected closing tag </%s>", name)
+			case closing && open[len(open)-1].name != name:
+				invalid(i, "closing tag </%s> does not match <%s>", name, open[len(open)-1].name)
+				open = open[:len(open)-1]
+			case closing:
+				open = open[:len(open)-1]
+			case !selfClosing:
+				open = append(open, openTag{name: name, offset: i})
+			}
+			for _, attr := range markupAttr.FindAllStringSubmatch(attrs, -1) {
+				if name != "span" {
+					invalid(i, "tag <%s> does not support attributes", name)
+					break
+				}
+				if !spanAttrs[attr[1]] {
+					invalid(i, "unknown span attribute %s", attr[1])
+				}
+			}
+			i += len(m[0]) - 1
+		}
+	}
+	for _, tag := range open {
+		invalid(tag.offset, "unclosed tag <%s>", tag.name)
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestValidatePango(t *testing.T) {
+	tests := []struct {
+		name   string
+		markup string
+		want   MarkupErrors
+	}{
+		{"plain", "plain text", nil},
+		{"tags", `<b>a</b> <span foreground="#ff0000" size='small'>b</span>`, nil},
+		{"entities", "a &amp; b &lt; c &#x41; &#65;", nil},
+		{"nested", "<b><i>a</i></b>", nil},
+		{"unescaped ampersand", "a & b", MarkupErrors{{Offset: 2, Reason: "unescaped &, use &amp;"}}},
+		{"unescaped lower than", "1 < 2", MarkupErrors{{Offset: 2, Reason: "unescaped < or malformed tag, use &lt;"}}},
+		{"unknown tag", "<blink>a</blink>", MarkupErrors{
+			{Offset: 0, Reason: "unknown tag <blink>"},
+			{Offset: 8, Reason: "unknown tag <blink>"},
+		}},
+		{"unclosed tag", "<b>a", MarkupErrors{{Offset: 0, Reason: "unclosed tag <b>"}}},
+		{"unexpected closing tag", "a</b>", MarkupErrors{{Offset: 1, Reason: "unexpected closing tag </b>"}}},
+		{"mismatched tags", "<b><i>a</b>", MarkupErrors{{Offset: 7, Reason: "closing tag </b> does not match <i>"}, {Offset: 0, Reason: "unclosed tag <b>"}}},
+		{"unknown attribute", `<span colour="red">a</span>`, MarkupErrors{{Offset: 0, Reason: "unknown span attribute colour"}}},
+		{"attribute of other tag", `<b weight="bold">a</b>`, MarkupErrors{{Offset: 0, Reason: "tag <b> does not support attributes"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePango(tt.markup)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("got %v", err)
+				}
+				return
+			}
+			var errs MarkupErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("got %v, want MarkupErrors", err)
+			}
+			if !reflect.DeepEqual(errs, tt.want) {
+				t.Errorf("got %v, want %v", errs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePangoBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		valid bool
+	}{
+		{"valid", Block{FullText: "<b>a</b>", ShortText: "<i>a</i>", Markup: Pango}, true},
+		{"invalid full text", Block{FullText: "<b>a", Markup: Pango}, false},
+		{"invalid short text", Block{FullText: "a", ShortText: "a & b", Markup: Pango}, false},
+		// without pango markup, & and < need no escaping
+		{"no markup", Block{FullText: "a & b < c"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.block.Validate(); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package i3bar
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Pause stops sending status lines until Resume is called.
+// Status lines sent in the meantime are coalesced, so that only
+// the latest one is sent once the stream is resumed or closed.
+// Pause is called automatically on the custom stop signal
+// declared in the header.
+//
+// This function is thread safe.
+func (s *Stream) Pause() {
+	// pausing never writes to the stream
+	_ = s.setPaused(true)
+}
+
+// Resume continues sending status lines after Pause and sends
+// the latest status line held back while the stream was paused.
+// Resume is called automatically on the continue signal declared in the
+// header, which may be the default SIGCONT if the stop signal is custom.
+//
+// This function is thread safe.
+func (s *Stream) Resume() error {
+	return s.setPaused(false)
+}
+
+// Paused reports whether the stream is paused.
+//
+// This function is thread safe.
+func (s *Stream) Paused() bool {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	return s.paused
+}
+
+// setPaused switches the paused state and calls the registered callback.
+func (s *Stream) setPaused(paused bool) error {
+	s.wMux.Lock()
+	if s.paused == paused {
+		s.wMux.Unlock()
+		return nil
+	}
+	s.paused = paused
+
+	var err error
+	fn := s.onPause
+	if !paused {
+		fn = s.onResume
+		if !s.closed {
+			err = s.flushPending()
+			if err == nil {
+				err = s.flushAsync()
+			}
+		}
+	}
+	s.wMux.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to send held back status line")
+	}
+	return nil
+}
+
+// hold stores a copy of b as pending status line until the stream is resumed.
+// The caller has to hold wMux.
+func (s *Stream) hold(b StatusLine) {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.pending, s.pendingBlink = b.Clone(), false
+}
@@ -0,0 +1,92 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloseWhilePaused(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"sync", nil},
+		{"min interval", []Option{WithMinInterval(time.Hour)}},
+		{"async", []Option{WithAsync(100)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, farewell := range []bool{false, true} {
+				var out bytes.Buffer
+				opts := append([]Option{WithFarewell(StatusLine{{FullText: "bye"}})}, tt.opts...)
+				s, err := NewStream(&out, nil, false, DefaultHeader(), opts...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := s.SendLine(StatusLine{{FullText: "first"}}); err != nil {
+					t.Fatal(err)
+				}
+				s.Pause()
+				if err := s.SendLine(StatusLine{{FullText: "latest"}}); err != nil {
+					t.Fatal(err)
+				}
+
+				want := "latest"
+				if farewell {
+					want = "bye"
+					err = s.Shutdown(context.Background())
+				} else {
+					err = s.Close()
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(out.String(), `"full_text":"`+want+`"`) {
+					t.Errorf("farewell %v: %q not sent on close while paused: %q", farewell, want, out.String())
+				}
+				if !strings.HasSuffix(out.String(), "]") {
+					t.Errorf("infinite array not closed: %q", out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReconnectResumes(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	resumed := 0
+	s.OnResume(func() { resumed++ })
+
+	// reconnecting a running stream does not resume it
+	if err := s.Reconnect(&bytes.Buffer{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if resumed != 0 {
+		t.Errorf("OnResume called %d times for a running stream", resumed)
+	}
+
+	s.Pause()
+	if err := s.SendLine(StatusLine{{FullText: "held"}}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := s.Reconnect(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Paused() {
+		t.Error("stream still paused after Reconnect")
+	}
+	if resumed != 1 {
+		t.Errorf("OnResume called %d times, want 1", resumed)
+	}
+	if !strings.Contains(out.String(), `"full_text":"held"`) {
+		t.Errorf("held status line not sent after Reconnect: %q", out.String())
+	}
+}
@@ -0,0 +1,45 @@
+package i3bar
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// anyTag matches any markup tag.
+var anyTag = regexp.MustCompile(`<[^>]*>`)
+
+// plainSeparator is drawn between blocks with a separator in plain text.
+const plainSeparator = " | "
+
+// PlainText renders a StatusLine as a single line of plain text.
+// Pango markup is removed and the icons of icons are replaced by their
+// names, e.g. "[battery]", so the text is suitable for screen readers.
+// icons may be nil to keep all glyphs.
+func PlainText(line StatusLine, icons IconSet) string {
+	names := icons.names()
+	var sb strings.Builder
+	var prev *Block
+	for _, b := range line {
+		if b == nil {
+			continue
+		}
+		// the separator of the previous block, so nil blocks
+		// at the end do not leave a trailing separator
+		if prev != nil {
+			if prev.Separator == nil || *prev.Separator {
+				sb.WriteString(plainSeparator)
+			} else {
+				sb.WriteString(" ")
+			}
+		}
+		prev = b
+
+		text := b.Text()
+		if b.Markup == Pango {
+			text = html.UnescapeString(anyTag.ReplaceAllString(text, ""))
+		}
+		sb.WriteString(names.Replace(text))
+	}
+	return strings.TrimSpace(sb.String())
+}
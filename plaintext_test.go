@@ -0,0 +1,51 @@
+package i3bar
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		line StatusLine
+		want string
+	}{
+		{"empty", nil, ""},
+		{"single", StatusLine{{FullText: "a"}}, "a"},
+		{"separators", StatusLine{{FullText: "a"}, {FullText: "b"}, {FullText: "c"}}, "a | b | c"},
+		{"no separator", StatusLine{{FullText: "a", Separator: Bool(false)}, {FullText: "b"}}, "a b"},
+		{"pango", StatusLine{{FullText: "<b>a</b> &amp; <span color='red'>b</span>", Markup: Pango}}, "a & b"},
+		// without pango markup, the text is kept as is
+		{"no markup", StatusLine{{FullText: "<b>a</b> &amp;"}}, "<b>a</b> &amp;"},
+		{"lazy text", StatusLine{{LazyText: TextFunc(func() string { return "lazy" })}}, "lazy"},
+		{"nil blocks", StatusLine{nil, {FullText: "a"}, nil, {FullText: "b"}, nil}, "a | b"},
+		{"surrounding spaces", StatusLine{{FullText: " a "}, {FullText: " b "}}, "a  |  b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.line, nil); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithPlainText(t *testing.T) {
+	var plain bytes.Buffer
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithPlainText(&plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []StatusLine{{{FullText: "a"}, {FullText: "b"}}, {{FullText: "c"}}} {
+		if err := s.SendLine(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := plain.String(), "a | b\nc\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package i3bar
+
+import "sync"
+
+// Comparison of a value against the threshold of a Rule.
+type Comparison int
+
+const (
+	// Above matches values greater than the threshold.
+	Above Comparison = iota
+	// Below matches values less than the threshold.
+	Below
+)
+
+// Rule of an UrgencyPolicy which applies to all blocks named Name
+// whose observed value matches the threshold.
+type Rule struct {
+	// Name of the blocks this rule applies to.
+	Name string
+
+	// Comparison of the observed value against Threshold.
+	Comparison Comparison
+
+	// Threshold the observed value is compared against.
+	Threshold float64
+
+	// Urgent marks matching blocks as urgent.
+	Urgent bool
+
+	// Color of the text of matching blocks, if set.
+	Color Color
+
+	// Background color of matching blocks, if set.
+	Background Color
+}
+
+// matches reports whether value matches the threshold of the Rule.
+func (r *Rule) matches(value float64) bool {
+	if r.Comparison == Below {
+		return value < r.Threshold
+	}
+	return value > r.Threshold
+}
+
+// UrgencyPolicy marks blocks as urgent or colors them depending on
+// the latest value observed for them, e.g. battery below 10 or cpu above 95.
+type UrgencyPolicy struct {
+	mux    sync.RWMutex
+	rules  []Rule
+	values map[string]float64
+}
+
+// NewUrgencyPolicy creates an UrgencyPolicy without any rules.
+func NewUrgencyPolicy() *UrgencyPolicy {
+	return &UrgencyPolicy{values: map[string]float64{}}
+}
+
+// Add adds rules to the policy. Rules are applied in the order they are added,
+// so later rules override the colors of earlier rules.
+func (p *UrgencyPolicy) Add(rules ...Rule) *UrgencyPolicy {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.rules = append(p.rules, rules...)
+	return p
+}
+
+// UrgentAbove marks blocks named name as urgent once their value exceeds threshold.
+func (p *UrgencyPolicy) UrgentAbove(name string, threshold float64) *UrgencyPolicy {
+	return p.Add(Rule{Name: name, Comparison: Above, Threshold: threshold, Urgent: true})
+}
+
+// UrgentBelow marks blocks named name as urgent once their value falls below threshold.
+func (p *UrgencyPolicy) UrgentBelow(name string, threshold float64) *UrgencyPolicy {
+	return p.Add(Rule{Name: name, Comparison: Below, Threshold: threshold, Urgent: true})
+}
+
+// Observe records the latest value of the blocks named name.
+func (p *UrgencyPolicy) Observe(name string, value float64) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.values[name] = value
+}
+
+// Apply applies all matching rules to b.
+// Blocks without an observed value are left untouched.
+func (p *UrgencyPolicy) Apply(b *Block) {
+	if b == nil {
+		return
+	}
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+
+	value, ok := p.values[b.Name]
+	if !ok {
+		return
+	}
+	for i := range p.rules {
+		r := &p.rules[i]
+		if r.Name != b.Name || !r.matches(value) {
+			continue
+		}
+		if r.Urgent {
+			b.Urgent = true
+		}
+		if r.Color != "" {
+			b.Color = r.Color
+		}
+		if r.Background != "" {
+			b.Background = r.Background
+		}
+	}
+}
+
+// Middleware returns a Middleware applying the policy to all blocks.
+// Use it before a Theme middleware, so that the theme's urgent
+// colors are applied to blocks marked as urgent by the policy.
+func (p *UrgencyPolicy) Middleware() Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			p.Apply(b)
+		}
+		return line
+	}
+}
@@ -0,0 +1,47 @@
+package i3bar
+
+import "testing"
+
+func TestUrgencyPolicy(t *testing.T) {
+	p := NewUrgencyPolicy().
+		UrgentBelow("battery", 10).
+		UrgentAbove("cpu", 95).
+		Add(Rule{Name: "cpu", Comparison: Above, Threshold: 50, Color: "#ffff00"},
+			Rule{Name: "cpu", Comparison: Above, Threshold: 80, Color: "#ff0000", Background: "#000000"})
+
+	tests := []struct {
+		name       string
+		block      string
+		value      float64
+		observed   bool
+		urgent     bool
+		color      Color
+		background Color
+	}{
+		{"not observed", "cpu", 0, false, false, "", ""},
+		{"below", "battery", 5, true, true, "", ""},
+		{"below threshold", "battery", 10, true, false, "", ""},
+		{"no match", "cpu", 50, true, false, "", ""},
+		{"color", "cpu", 60, true, false, "#ffff00", ""},
+		// later rules override the colors of earlier rules
+		{"override", "cpu", 90, true, false, "#ff0000", "#000000"},
+		{"above", "cpu", 99, true, true, "#ff0000", "#000000"},
+		{"no rules", "memory", 99, true, false, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.observed {
+				p.Observe(tt.block, tt.value)
+			}
+			line := p.Middleware()(StatusLine{{Name: tt.block, FullText: "x"}})
+			b := line[0]
+			if b.Urgent != tt.urgent || b.Color != tt.color || b.Background != tt.background {
+				t.Errorf("got urgent %v color %q background %q, want urgent %v color %q background %q",
+					b.Urgent, b.Color, b.Background, tt.urgent, tt.color, tt.background)
+			}
+		})
+	}
+
+	// applying to nil blocks is a no-op
+	p.Apply(nil)
+}
@@ -0,0 +1,79 @@
+package i3bar
+
+// PowerlineArrow is the left pointing powerline arrow glyph.
+const PowerlineArrow = "\ue0b2"
+
+// Powerline returns a Middleware rendering the StatusLine as chained
+// powerline segments. Each block is prefixed by glyph (e.g. PowerlineArrow)
+// drawn in the background color of the block on the background color
+// of the preceding block. The first block is prefixed on barBackground,
+// which should be the background color of the bar. If barBackground
+// is empty, the first block is not prefixed.
+// Separators and the gaps between blocks are removed and all blocks
+// are converted to Pango markup.
+func Powerline(glyph string, barBackground Color) Middleware {
+	return func(line StatusLine) StatusLine {
+		prev := barBackground
+		first := true
+		for _, b := range line {
+			if b == nil {
+				continue
+			}
+			prefix := ""
+			if !first || barBackground != "" {
+				prefix = powerlineGlyph(glyph, b.Background, prev)
+			}
+			prev = b.Background
+			first = false
+
+			toPango(b)
+			if b.FullText != "" || b.LazyText == nil {
+				b.FullText = prefix + b.FullText
+			} else {
+				lazy := b.LazyText
+				b.LazyText = TextFunc(func() string {
+					return prefix + lazy.String()
+				})
+			}
+			if b.ShortText != "" {
+				b.ShortText = prefix + b.ShortText
+			}
+
+			b.Separator = Bool(false)
+			// separator_block_width is omitted if 0, so use an extra key
+			b.SeparatorBlockWidth = 0
+			if b.Extra == nil {
+				b.Extra = map[string]interface{}{}
+			}
+			b.Extra["separator_block_width"] = 0
+		}
+		return line
+	}
+}
+
+// powerlineGlyph renders glyph in the color fg on the color bg.
+func powerlineGlyph(glyph string, fg, bg Color) string {
+	sb := Span()
+	if c, err := ParseColor(string(fg)); err == nil {
+		sb.Foreground(c)
+	}
+	if c, err := ParseColor(string(bg)); err == nil {
+		sb.Background(c)
+	}
+	return sb.Text(glyph)
+}
+
+// toPango converts the texts of b to Pango markup by escaping them.
+func toPango(b *Block) {
+	if b.Markup == Pango {
+		return
+	}
+	b.Markup = Pango
+	b.FullText = PangoEscape(b.FullText)
+	b.ShortText = PangoEscape(b.ShortText)
+	if lazy := b.LazyText; lazy != nil {
+		b.LazyText = TextFunc(func() string {
+			return PangoEscape(lazy.String())
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPowerline(t *testing.T) {
+	red := `<span foreground="#ff0000" background="#000000">&gt;</span>`
+	blue := `<span foreground="#0000ff" background="#ff0000">&gt;</span>`
+
+	tests := []struct {
+		name    string
+		bar     Color
+		line    StatusLine
+		full    []string
+		short   []string
+		markups []Markup
+	}{
+		{
+			name:    "chained",
+			bar:     "#000000",
+			line:    StatusLine{{FullText: "a", Background: "#ff0000"}, {FullText: "b", Background: "#0000ff"}},
+			full:    []string{red + "a", blue + "b"},
+			short:   []string{"", ""},
+			markups: []Markup{Pango, Pango},
+		},
+		{
+			name:    "first without bar background",
+			line:    StatusLine{{FullText: "a", Background: "#ff0000"}, {FullText: "b", Background: "#0000ff"}},
+			full:    []string{"a", blue + "b"},
+			short:   []string{"", ""},
+			markups: []Markup{Pango, Pango},
+		},
+		{
+			name:    "escaped text",
+			bar:     "#000000",
+			line:    StatusLine{{FullText: "a<b", ShortText: "<", Background: "#ff0000"}},
+			full:    []string{red + "a&lt;b"},
+			short:   []string{red + "&lt;"},
+			markups: []Markup{Pango},
+		},
+		{
+			name:    "pango text",
+			bar:     "#000000",
+			line:    StatusLine{{FullText: "<b>a</b>", Background: "#ff0000", Markup: Pango}},
+			full:    []string{red + "<b>a</b>"},
+			short:   []string{""},
+			markups: []Markup{Pango},
+		},
+		{
+			name:    "lazy text",
+			bar:     "#000000",
+			line:    StatusLine{{LazyText: TextFunc(func() string { return "a<b" }), Background: "#ff0000"}},
+			full:    []string{red + "a&lt;b"},
+			short:   []string{""},
+			markups: []Markup{Pango},
+		},
+		{
+			name:    "nil blocks",
+			line:    StatusLine{nil, {FullText: "a", Background: "#ff0000"}, nil, {FullText: "b", Background: "#0000ff"}},
+			full:    []string{"a", blue + "b"},
+			short:   []string{"", ""},
+			markups: []Markup{Pango, Pango},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var full, short []string
+			var markups []Markup
+			for _, b := range Powerline(">", tt.bar)(tt.line) {
+				if b == nil {
+					continue
+				}
+				full = append(full, b.Text())
+				short = append(short, b.ShortText)
+				markups = append(markups, b.Markup)
+
+				// the gaps between blocks are removed
+				if b.Separator == nil || *b.Separator || b.Extra["separator_block_width"] != 0 {
+					t.Errorf("block %q keeps its separator", b.Text())
+				}
+			}
+			if !reflect.DeepEqual(full, tt.full) {
+				t.Errorf("got full texts %q, want %q", full, tt.full)
+			}
+			if !reflect.DeepEqual(short, tt.short) {
+				t.Errorf("got short texts %q, want %q", short, tt.short)
+			}
+			if !reflect.DeepEqual(markups, tt.markups) {
+				t.Errorf("got markups %v, want %v", markups, tt.markups)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package i3bar
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultBarWidth is the width of a ProgressBar in characters if no width is set.
+const DefaultBarWidth = 10
+
+// eighths are the block elements filling a character in steps of 1/8.
+var eighths = []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉", "█"}
+
+// ProgressBar renders a fraction as a bar of fixed width,
+// e.g. for battery, volume or disk usage blocks.
+type ProgressBar struct {
+	// Width of the bar in characters. DefaultBarWidth is used if not set.
+	Width int
+
+	// Fill are the characters for partially up to fully filled characters
+	// in ascending order. Defaults to the block elements ▏ to █.
+	Fill []string
+
+	// Empty is the character for unfilled characters. Defaults to a space.
+	Empty string
+}
+
+// Render renders fraction, which is clamped to 0.0 to 1.0.
+func (p ProgressBar) Render(fraction float64) string {
+	width := p.Width
+	if width <= 0 {
+		width = DefaultBarWidth
+	}
+	fill := p.Fill
+	if len(fill) == 0 {
+		fill = eighths
+	}
+	empty := p.Empty
+	if empty == "" {
+		empty = " "
+	}
+
+	fraction = math.Max(0, math.Min(1, fraction))
+	if math.IsNaN(fraction) {
+		fraction = 0
+	}
+	steps := int(math.Round(fraction * float64(width*len(fill))))
+	full, partial := steps/len(fill), steps%len(fill)
+
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat(fill[len(fill)-1], full))
+	if partial > 0 {
+		sb.WriteString(fill[partial-1])
+		full++
+	}
+	sb.WriteString(strings.Repeat(empty, width-full))
+	return sb.String()
+}
+
+// RenderPercent renders percent, which is clamped to 0 to 100.
+func (p ProgressBar) RenderPercent(percent float64) string {
+	return p.Render(percent / 100)
+}
+
+// Bar renders fraction as a bar of width characters
+// using the default ProgressBar characters.
+func Bar(fraction float64, width int) string {
+	return ProgressBar{Width: width}.Render(fraction)
+}
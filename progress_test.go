@@ -0,0 +1,50 @@
+package i3bar
+
+import (
+	"math"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		name     string
+		bar      ProgressBar
+		fraction float64
+		want     string
+	}{
+		{"empty", ProgressBar{Width: 4}, 0, "    "},
+		{"full", ProgressBar{Width: 4}, 1, "████"},
+		{"half", ProgressBar{Width: 4}, 0.5, "██  "},
+		{"partial", ProgressBar{Width: 4}, 0.3, "█▎  "},
+		{"smallest step", ProgressBar{Width: 4}, 1.0 / 32, "▏   "},
+		{"clamped below", ProgressBar{Width: 4}, -1, "    "},
+		{"clamped above", ProgressBar{Width: 4}, 2, "████"},
+		{"nan", ProgressBar{Width: 4}, math.NaN(), "    "},
+		{"default width", ProgressBar{}, 0.5, "█████     "},
+		{"custom characters", ProgressBar{Width: 4, Fill: []string{"-", "="}, Empty: "."}, 0.625, "==-."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.bar.Render(tt.fraction)
+			if got != tt.want {
+				t.Errorf("Render(%v) = %q, want %q", tt.fraction, got, tt.want)
+			}
+			// the bar never changes its width
+			width := tt.bar.Width
+			if width == 0 {
+				width = DefaultBarWidth
+			}
+			if n := utf8.RuneCountInString(got); n != width {
+				t.Errorf("got %d characters, want %d", n, width)
+			}
+		})
+	}
+
+	if got := (ProgressBar{Width: 4}).RenderPercent(50); got != "██  " {
+		t.Errorf("RenderPercent(50) = %q", got)
+	}
+	if got := Bar(0.25, 4); got != "█   " {
+		t.Errorf("Bar(0.25, 4) = %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+package i3bar
+
+// Prototype creates blocks sharing the same defaults,
+// e.g. alignment, markup, separator settings and colors.
+type Prototype struct {
+	// Block holding the defaults. Name and Instance are ignored.
+	Block Block
+
+	// Theme applied to new blocks after copying the defaults.
+	// Colors set on Block take precedence over the Theme.
+	Theme *Theme
+}
+
+// New creates a Block identified by name and instance
+// which inherits all defaults of the Prototype.
+func (p *Prototype) New(name, instance string) *Block {
+	b := p.Block.Clone()
+	b.Name = name
+	b.Instance = instance
+	if p.Theme != nil {
+		p.Theme.Apply(b)
+	}
+	return b
+}
+
+// Builder starts building a Block named name which inherits
+// all defaults of the Prototype.
+func (p *Prototype) Builder(name string) *BlockBuilder {
+	return &BlockBuilder{b: *p.New(name, "")}
+}
+
+// SetPrototype sets the Prototype used by NewBlock.
+// This function is thread safe.
+func (s *Stream) SetPrototype(p *Prototype) {
+	s.mwMux.Lock()
+	defer s.mwMux.Unlock()
+	s.prototype = p
+}
+
+// NewBlock creates a Block identified by name and instance which
+// inherits the defaults of the Prototype set by SetPrototype.
+// This function is thread safe.
+func (s *Stream) NewBlock(name, instance string) *Block {
+	s.mwMux.RLock()
+	p := s.prototype
+	s.mwMux.RUnlock()
+	if p == nil {
+		return &Block{Name: name, Instance: instance}
+	}
+	return p.New(name, instance)
+}
@@ -0,0 +1,66 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrototype(t *testing.T) {
+	tests := []struct {
+		name      string
+		prototype *Prototype
+		want      Block
+	}{
+		{
+			name:      "empty",
+			prototype: &Prototype{},
+			want:      Block{Name: "cpu", Instance: "0"},
+		},
+		{
+			name:      "defaults",
+			prototype: &Prototype{Block: Block{Name: "ignored", Instance: "ignored", Align: Right, Separator: Bool(false)}},
+			want:      Block{Name: "cpu", Instance: "0", Align: Right, Separator: Bool(false)},
+		},
+		{
+			name: "theme",
+			prototype: &Prototype{
+				Block: Block{Color: "#00ff00"},
+				Theme: &Theme{Foreground: "#ffffff", Background: "#000000"},
+			},
+			// colors of the block take precedence over the theme
+			want: Block{Name: "cpu", Instance: "0", Color: "#00ff00", Background: "#000000"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s Stream
+			s.SetPrototype(tt.prototype)
+			got := s.NewBlock("cpu", "0")
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+
+			want := tt.want
+			want.FullText = "x"
+			if got := tt.prototype.Builder("cpu").Instance("0").Text("x").MustBuild(); !reflect.DeepEqual(*got, want) {
+				t.Errorf("Builder built %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestPrototypeIsolation(t *testing.T) {
+	p := &Prototype{Block: Block{Separator: Bool(true)}}
+	a, b := p.New("a", ""), p.New("b", "")
+	*a.Separator = false
+	if !*b.Separator || !*p.Block.Separator {
+		t.Error("blocks share the separator setting of the prototype")
+	}
+}
+
+func TestNewBlockWithoutPrototype(t *testing.T) {
+	var s Stream
+	if got, want := s.NewBlock("cpu", "0"), (&Block{Name: "cpu", Instance: "0"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
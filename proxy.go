@@ -0,0 +1,125 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// Proxy reads status lines of another i3bar protocol producer like i3status,
+// so that they can be modified and re-emitted on a Stream.
+type Proxy struct {
+	r      io.Reader
+	d      *json.Decoder
+	cmd    *exec.Cmd
+	header Header
+}
+
+// NewProxy starts reading the i3bar protocol stream of another producer from r.
+// The protocol header and the start of the infinite array are read immediately.
+func NewProxy(r io.Reader) (*Proxy, error) {
+	p := &Proxy{
+		r: r,
+		d: json.NewDecoder(r),
+	}
+
+	if err := p.d.Decode(&p.header); err != nil {
+		return nil, withKind(ErrProtocol, errors.Wrap(err, "Failed to read header"))
+	}
+
+	tok, err := p.d.Token()
+	if err != nil {
+		return nil, withKind(ErrProtocol, errors.Wrap(err, "Failed to read start of infinite json array"))
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, withKind(ErrProtocol, errors.Errorf("unexpected start of infinite json array: %v", tok))
+	}
+
+	return p, nil
+}
+
+// NewProxyCommand spawns the command name with args and proxies its standard output.
+// The command is killed on Close.
+func NewProxyCommand(name string, args ...string) (*Proxy, error) {
+	cmd := exec.Command(name, args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to connect to command output")
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to start %s", name)
+	}
+
+	p, err := NewProxy(stdout)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	p.cmd = cmd
+	return p, nil
+}
+
+// Header returns the protocol header sent by the producer.
+func (p *Proxy) Header() Header {
+	return p.header
+}
+
+// Next reads the next status line of the producer.
+// io.EOF is returned once the producer closed its stream.
+// A status line cut off by the end of the stream is dropped.
+func (p *Proxy) Next() (StatusLine, error) {
+	if !p.d.More() {
+		return nil, io.EOF
+	}
+	var line StatusLine
+	if err := p.d.Decode(&line); err != nil {
+		if isEndOfStream(err) {
+			return nil, io.EOF
+		}
+		return nil, withKind(ErrProtocol, errors.Wrap(err, "Failed to read status line"))
+	}
+	return line, nil
+}
+
+// Run reads all status lines of the producer, passes them to fn
+// and sends the returned status line to s.
+// fn may inject, modify or remove blocks. If fn is nil,
+// status lines are sent unmodified.
+// Run returns nil once the producer closed its stream.
+func (p *Proxy) Run(s *Stream, fn func(StatusLine) StatusLine) error {
+	for {
+		line, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fn != nil {
+			line = fn(line)
+		}
+		if err := s.SendLine(line); err != nil {
+			return err
+		}
+	}
+}
+
+// Close stops the spawned command. The reader passed
+// to NewProxy is not closed.
+func (p *Proxy) Close() error {
+	if p.cmd == nil {
+		return nil
+	}
+	cmd := p.cmd
+	p.cmd = nil
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return errors.Wrap(err, "Failed to stop command")
+	}
+	// the command has been killed, so its exit status is meaningless
+	cmd.Wait()
+	return nil
+}
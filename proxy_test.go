@@ -0,0 +1,61 @@
+package i3bar
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestProxyNext(t *testing.T) {
+	const header = "{\"version\":1}\n[\n"
+	a := StatusLine{{FullText: "a"}}
+	b := StatusLine{{Name: "b", FullText: "b"}}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []StatusLine
+		wantErr error
+	}{
+		{"closed array", header + `[{"full_text":"a"}],[{"name":"b","full_text":"b"}]]`, []StatusLine{a, b}, io.EOF},
+		{"unterminated array", header + "[{\"full_text\":\"a\"}]\n,[{\"name\":\"b\",\"full_text\":\"b\"}]\n", []StatusLine{a, b}, io.EOF},
+		{"trailing comma", header + "[{\"full_text\":\"a\"}],\n", []StatusLine{a}, io.EOF},
+		{"truncated status line", header + "[{\"full_text\":\"a\"}]\n,[{\"name\":\"b\",\"full_", []StatusLine{a}, io.EOF},
+		{"truncated block", header + "[{\"full_text\":\"a\"}]\n,[{\"name\":\"b\"},", []StatusLine{a}, io.EOF},
+		{"malformed status line", header + "[{\"full_text\":\"a\"}]\n,[{\"full_text\":1}]", []StatusLine{a}, ErrProtocol},
+		{"syntax error", header + "[{\"full_text\":\"a\"}]\n,[{\"full_text\" \"b\"}]", []StatusLine{a}, ErrProtocol},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProxy(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []StatusLine
+			for {
+				line, err := p.Next()
+				if err != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("got error %v, want %v", err, tt.wantErr)
+					}
+					break
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProxyInvalidHeader(t *testing.T) {
+	for _, input := range []string{"", "{\"version\":1}", "{\"version\":1}\n{", "[]"} {
+		if _, err := NewProxy(strings.NewReader(input)); !errors.Is(err, ErrProtocol) {
+			t.Errorf("NewProxy(%q): got %v, want ErrProtocol", input, err)
+		}
+	}
+}
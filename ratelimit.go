@@ -0,0 +1,64 @@
+package i3bar
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// throttle stores a copy of b as pending status line if the minimum interval
+// since the last status line has not passed yet and returns true in this case.
+// The pending status line is encoded once it is sent, so that lazy texts
+// of coalesced status lines are never evaluated.
+// The caller has to hold wMux.
+func (s *Stream) throttle(b StatusLine) bool {
+	if s.minInterval <= 0 {
+		return false
+	}
+	wait := s.minInterval - time.Since(s.lastSent)
+	if wait <= 0 {
+		// a newer status line supersedes the pending one
+		s.pending, s.pendingBlink = nil, false
+		return false
+	}
+	s.pending, s.pendingBlink = b.Clone(), false
+	if s.timer == nil {
+		s.timer = time.AfterFunc(wait, s.sendPending)
+	}
+	return true
+}
+
+// sendPending sends the pending status line once the minimum interval has passed.
+func (s *Stream) sendPending() {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.timer = nil
+	if s.closed || s.paused || s.pending == nil {
+		return
+	}
+	// the timer may fire late after another status line has been sent
+	if wait := s.minInterval - time.Since(s.lastSent); wait > 0 {
+		s.timer = time.AfterFunc(wait, s.sendPending)
+		return
+	}
+	if err := s.flushPending(); err != nil {
+		s.reportError(errors.Wrap(err, "Failed to send pending status line"))
+	}
+}
+
+// flushPending writes the pending status line regardless of the minimum interval.
+// The caller has to hold wMux.
+func (s *Stream) flushPending() error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.pending == nil {
+		return nil
+	}
+	b, blinking := s.pending, s.pendingBlink
+	s.pending, s.pendingBlink = nil, false
+	s.blinking = blinking
+	defer func() { s.blinking = false }()
+	return s.encodeLine(b)
+}
@@ -0,0 +1,91 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMinInterval(t *testing.T) {
+	evaluated := 0
+	lazy := &Block{LazyText: TextFunc(func() string {
+		evaluated++
+		return "lazy"
+	})}
+
+	tests := []struct {
+		name     string
+		interval time.Duration
+		lines    []StatusLine
+		want     []string
+	}{
+		{
+			name:  "disabled",
+			lines: []StatusLine{{{FullText: "a"}}, {{FullText: "b"}}, {{FullText: "c"}}},
+			want:  []string{`[[{"full_text":"a"}]`, `,[{"full_text":"b"}]`, `,[{"full_text":"c"}]`, "]"},
+		},
+		{
+			// the latest status line held back is sent on close
+			name:     "coalesced",
+			interval: time.Hour,
+			lines:    []StatusLine{{{FullText: "a"}}, {{FullText: "b"}}, {{FullText: "c"}}},
+			want:     []string{`[[{"full_text":"a"}]`, `,[{"full_text":"c"}]`, "]"},
+		},
+		{
+			// lazy texts of superseded status lines are never evaluated
+			name:     "lazy text superseded",
+			interval: time.Hour,
+			lines:    []StatusLine{{{FullText: "a"}}, {lazy}, {{FullText: "c"}}},
+			want:     []string{`[[{"full_text":"a"}]`, `,[{"full_text":"c"}]`, "]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluated = 0
+			var out lockedBuffer
+			s, err := NewStream(&out, nil, false, DefaultHeader(), WithMinInterval(tt.interval))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range tt.lines {
+				if err := s.SendLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := statusLines(out.String()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got status lines %q, want %q", got, tt.want)
+			}
+			if evaluated != 0 {
+				t.Errorf("lazy text of superseded status line evaluated %d times", evaluated)
+			}
+		})
+	}
+}
+
+func TestMinIntervalPending(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	var out lockedBuffer
+	s, err := NewStream(&out, nil, false, DefaultHeader(), WithMinInterval(interval))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	start := time.Now()
+	for _, text := range []string{"a", "b", "c"} {
+		if err := s.SendLine(StatusLine{{FullText: text}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the pending status line is sent once the interval has passed
+	waitFor(t, "pending status line", func() bool { return len(statusLines(out.String())) == 2 })
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Errorf("pending status line sent after %v, want at least %v", elapsed, interval)
+	}
+	if got := statusLines(out.String())[1]; got != `,[{"full_text":"c"}]` {
+		t.Errorf("got pending status line %q, want the latest one", got)
+	}
+}
@@ -0,0 +1,98 @@
+package i3bar
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Reconnect switches the stream to a new i3bar instance, e.g. after
+// i3bar got restarted. The protocol header and the opening bracket of the
+// infinite json array are sent to w, followed by the last status line
+// and any status line which was held back in the meantime.
+// Middlewares, prototype, rate limiting and all other state of the
+// stream are kept.
+//
+// If r is not nil, click events are read from r from now on and delivered
+// on a new channel returned by Events. The channel of the previous reader
+// is closed. If r is nil, the previous reader is kept.
+//
+// Reconnect works on disconnected streams as well and starts their
+// goroutines again. As the click event reader of a disconnected stream
+// has been stopped, click events are only read again if r is not nil.
+// Reconnect returns ErrClosed once Close has been called.
+//
+// A paused stream is resumed, as the new i3bar instance did not send
+// the stop signal, and the callback registered by OnResume is called.
+// This function is thread safe.
+func (s *Stream) Reconnect(w io.Writer, r io.Reader) error {
+	s.wMux.Lock()
+	resumed, err := s.reconnect(w, r)
+	fn := s.onResume
+	s.wMux.Unlock()
+
+	if resumed && fn != nil {
+		fn()
+	}
+	return err
+}
+
+// reconnect implements Reconnect and reports whether
+// the stream has been resumed.
+// The caller has to hold wMux.
+func (s *Stream) reconnect(w io.Writer, r io.Reader) (resumed bool, err error) {
+	s.dMux.Lock()
+	if s.shut {
+		s.dMux.Unlock()
+		return false, ErrClosed
+	}
+	restart := false
+	select {
+	case <-s.done:
+		// the goroutines have been stopped on disconnect
+		s.done, s.closeOnce = make(chan struct{}), &sync.Once{}
+		restart = true
+	default:
+	}
+	s.dMux.Unlock()
+
+	// buffered data belongs to the previous i3bar
+	s.out = w
+	s.w.Reset(w)
+	s.closed = false
+	s.sent = false
+	resumed, s.paused = s.paused, false
+
+	// started before the handshake, so that a failing handshake
+	// stops them again
+	if restart {
+		s.start(r)
+	} else if r != nil {
+		s.startReader(r, s.stopped())
+	}
+	if err := s.handshake(); err != nil {
+		return resumed, errors.Wrap(err, "Failed to reconnect")
+	}
+
+	if len(s.last) > 0 {
+		// writeLine records the line again, so write a copy of it
+		if err := s.writeLine(append([]byte(nil), s.last...)); err != nil {
+			return resumed, errors.Wrap(err, "Failed to resend last status line")
+		}
+	}
+	if err := s.flushPending(); err != nil {
+		return resumed, errors.Wrap(err, "Failed to send pending status line")
+	}
+	if err := s.flushAsync(); err != nil {
+		return resumed, errors.Wrap(err, "Failed to send pending status line")
+	}
+	return resumed, nil
+}
+
+// SetWriter switches the stream to the new writer w like Reconnect,
+// but keeps reading click events from the current reader.
+// This function is thread safe.
+func (s *Stream) SetWriter(w io.Writer) error {
+	return s.Reconnect(w, nil)
+}
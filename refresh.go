@@ -0,0 +1,71 @@
+package i3bar
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/pkg/errors"
+)
+
+// OnRefresh registers fn to be called on every Refresh,
+// so that modules can update immediately.
+//
+// This function is thread safe.
+func (s *Stream) OnRefresh(fn func()) {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.refreshListeners = append(s.refreshListeners, fn)
+}
+
+// Refresh sends the last status line again and notifies all listeners
+// registered by OnRefresh, e.g. after resuming from suspend.
+// Nothing is sent while the stream is paused, but listeners are notified.
+//
+// This function is thread safe.
+func (s *Stream) Refresh() error {
+	s.wMux.Lock()
+	if s.closed {
+		s.wMux.Unlock()
+		return ErrClosed
+	}
+	var err error
+	if len(s.last) > 0 && !s.paused {
+		// writeLine records the line again, so write a copy of it
+		err = s.writeLine(append([]byte(nil), s.last...))
+	}
+	listeners := s.refreshListeners
+	s.wMux.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to resend last status line")
+	}
+	return nil
+}
+
+// handleRefresh calls Refresh whenever sig is received.
+func (s *Stream) handleRefresh(sig os.Signal) {
+	s.refresh = make(chan os.Signal, 1)
+	signal.Notify(s.refresh, sig)
+
+	go func(c <-chan os.Signal) {
+		for range c {
+			if err := s.Refresh(); err != nil {
+				s.reportError(err)
+			}
+		}
+	}(s.refresh)
+}
+
+// stopRefresh removes the installed refresh signal handler.
+// The caller has to hold wMux.
+func (s *Stream) stopRefresh() {
+	if s.refresh == nil {
+		return
+	}
+	signal.Stop(s.refresh)
+	close(s.refresh)
+	s.refresh = nil
+}
@@ -0,0 +1,97 @@
+package i3bar
+
+import (
+	"bytes"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []StatusLine
+		paused bool
+		want   []string
+	}{
+		{"nothing sent", nil, false, nil},
+		{"resend last line", []StatusLine{{{FullText: "a"}}, {{FullText: "b"}}}, false, []string{"a", "b", "b"}},
+		{"paused", []StatusLine{{{FullText: "a"}}}, true, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, false, DefaultHeader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			refreshed := 0
+			s.OnRefresh(func() { refreshed++ })
+			s.OnRefresh(func() { refreshed++ })
+			for _, line := range tt.lines {
+				if err := s.SendLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.paused {
+				s.Pause()
+			}
+			if err := s.Refresh(); err != nil {
+				t.Fatal(err)
+			}
+			// listeners are notified even if nothing is sent
+			if refreshed != 2 {
+				t.Errorf("listeners called %d times, want 2", refreshed)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, line := range sentLines(t, &out) {
+				got = append(got, line[0].FullText)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshClosed(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Refresh(); !errors.Is(err, ErrClosed) {
+		t.Errorf("got %v, want ErrClosed", err)
+	}
+}
+
+func TestRefreshSignal(t *testing.T) {
+	var out lockedBuffer
+	s, err := NewStream(&out, nil, false, DefaultHeader(), WithRefreshSignal(syscall.SIGUSR1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	var refreshed int32
+	s.OnRefresh(func() { atomic.AddInt32(&refreshed, 1) })
+	if err := s.SendLine(StatusLine{{FullText: "a"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "refresh", func() bool { return atomic.LoadInt32(&refreshed) == 1 })
+	if got := len(statusLines(out.String())); got != 2 {
+		t.Errorf("sent %d status lines, want 2", got)
+	}
+}
@@ -0,0 +1,59 @@
+package i3bar
+
+// Region is a named part of a block which handles its own click events,
+// e.g. the previous, play and next buttons of a media block.
+type Region struct {
+	// Name of the region.
+	Name string
+
+	// Weight is the share of the block width covered by the region
+	// relative to the other regions. Regions without weight count as 1.
+	Weight float64
+
+	// Handler is called for click events within the region.
+	Handler func(ev ClickEvent)
+}
+
+// Regions returns a click handler which splits the width of the clicked
+// block from left to right into regions and dispatches each click event
+// to the region containing its RelativeX coordinate.
+// Click events without block width are dropped.
+func Regions(regions ...Region) func(ev ClickEvent) {
+	return func(ev ClickEvent) {
+		if r, ok := RegionAt(ev, regions); ok && r.Handler != nil {
+			r.Handler(ev)
+		}
+	}
+}
+
+// RegionAt returns the region of regions containing the click event.
+func RegionAt(ev ClickEvent, regions []Region) (Region, bool) {
+	f, ok := ev.Fraction()
+	if !ok || len(regions) == 0 {
+		return Region{}, false
+	}
+
+	var total float64
+	for _, r := range regions {
+		total += regionWeight(r)
+	}
+	x := f * total
+
+	var end float64
+	for _, r := range regions {
+		end += regionWeight(r)
+		if x < end {
+			return r, true
+		}
+	}
+	// clicks on the right border belong to the last region
+	return regions[len(regions)-1], true
+}
+
+// regionWeight returns the weight of r defaulting to 1.
+func regionWeight(r Region) float64 {
+	if r.Weight <= 0 {
+		return 1
+	}
+	return r.Weight
+}
@@ -0,0 +1,76 @@
+package i3bar
+
+import "testing"
+
+func TestRegionAt(t *testing.T) {
+	media := []Region{{Name: "prev"}, {Name: "play", Weight: 2}, {Name: "next"}}
+
+	tests := []struct {
+		name    string
+		ev      ClickEvent
+		regions []Region
+		want    string
+	}{
+		{"left border", ClickEvent{RelativeX: 0, Width: 100}, media, "prev"},
+		{"first region", ClickEvent{RelativeX: 24, Width: 100}, media, "prev"},
+		{"weighted region start", ClickEvent{RelativeX: 25, Width: 100}, media, "play"},
+		{"weighted region end", ClickEvent{RelativeX: 74, Width: 100}, media, "play"},
+		{"last region", ClickEvent{RelativeX: 75, Width: 100}, media, "next"},
+		{"right border", ClickEvent{RelativeX: 100, Width: 100}, media, "next"},
+		{"outside", ClickEvent{RelativeX: 150, Width: 100}, media, "next"},
+		{"negative weight", ClickEvent{RelativeX: 40, Width: 100}, []Region{{Name: "a", Weight: -1}, {Name: "b"}}, "a"},
+		{"single region", ClickEvent{RelativeX: 99, Width: 100}, []Region{{Name: "a"}}, "a"},
+		{"no width", ClickEvent{RelativeX: 10}, media, ""},
+		{"no regions", ClickEvent{RelativeX: 10, Width: 100}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := RegionAt(tt.ev, tt.regions)
+			if ok != (tt.want != "") || r.Name != tt.want {
+				t.Errorf("got region %q (%v), want %q", r.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegions(t *testing.T) {
+	var got []string
+	handler := Regions(
+		Region{Name: "a", Handler: func(ev ClickEvent) { got = append(got, "a") }},
+		// regions without handler ignore clicks
+		Region{Name: "b"},
+		Region{Name: "c", Handler: func(ev ClickEvent) { got = append(got, "c") }},
+	)
+	for _, x := range []int{10, 50, 90} {
+		handler(ClickEvent{RelativeX: x, Width: 90})
+	}
+	handler(ClickEvent{RelativeX: 10})
+	if len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("got %q, want [a c]", got)
+	}
+}
+
+func TestClickEventFraction(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   ClickEvent
+		want float64
+		ok   bool
+	}{
+		{"left border", ClickEvent{RelativeX: 0, Width: 200}, 0, true},
+		{"middle", ClickEvent{RelativeX: 50, Width: 200}, 0.25, true},
+		{"right border", ClickEvent{RelativeX: 200, Width: 200}, 1, true},
+		// positions outside of the block are clamped
+		{"negative", ClickEvent{RelativeX: -5, Width: 200}, 0, true},
+		{"beyond", ClickEvent{RelativeX: 250, Width: 200}, 1, true},
+		{"no width", ClickEvent{RelativeX: 50}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.ev.Fraction()
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Fraction() = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
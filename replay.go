@@ -0,0 +1,72 @@
+package i3bar
+
+import (
+	"time"
+)
+
+// replayEvent is a click event kept for handlers registered later.
+type replayEvent struct {
+	ev ClickEvent
+	at time.Time
+}
+
+// record keeps ev in the replay buffer and returns the oldest
+// events if they had to be dropped for ev.
+// The caller has to hold rMux.
+func (s *Stream) record(ev ClickEvent) []ClickEvent {
+	var dropped []ClickEvent
+	if n := len(s.replay) - s.replaySize + 1; n > 0 {
+		for _, r := range s.replay[:n] {
+			dropped = append(dropped, r.ev)
+		}
+		s.replay = append(s.replay[:0], s.replay[n:]...)
+	}
+	s.replay = append(s.replay, replayEvent{ev: ev, at: time.Now()})
+	return dropped
+}
+
+// queueReplay moves all buffered events matching route to the replay queue
+// and wakes up the click event reader, so that they are dispatched in order
+// with live events. Events older than replayAge are dropped and returned.
+// The caller has to hold rMux.
+func (s *Stream) queueReplay(route clickRoute) []ClickEvent {
+	var expired []ClickEvent
+	queued := false
+	kept := s.replay[:0]
+	for _, r := range s.replay {
+		switch {
+		case s.replayAge > 0 && time.Since(r.at) > s.replayAge:
+			expired = append(expired, r.ev)
+		case r.ev.Name == route.name && (route.instance == "" || r.ev.Instance == route.instance):
+			s.replayQueue = append(s.replayQueue, r.ev)
+			queued = true
+		default:
+			kept = append(kept, r)
+		}
+	}
+	s.replay = kept
+
+	if queued {
+		select {
+		case s.replayNotify <- struct{}{}:
+		default:
+		}
+	}
+	return expired
+}
+
+// takeQueued removes and returns all events queued for replay.
+func (s *Stream) takeQueued() []ClickEvent {
+	s.rMux.Lock()
+	defer s.rMux.Unlock()
+	queued := s.replayQueue
+	s.replayQueue = nil
+	return queued
+}
+
+// dropClicks counts click events dropped from the replay buffer.
+func (s *Stream) dropClicks(evs []ClickEvent) {
+	for _, ev := range evs {
+		s.countClick(clickRoute{name: ev.Name, instance: ev.Instance}, ClickDropped, 0)
+	}
+}
@@ -0,0 +1,154 @@
+package i3bar
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// replayStream returns a stream with click replay enabled and the
+// writer for its click events.
+func replayStream(t *testing.T, opts ...Option) (*Stream, io.Writer) {
+	t.Helper()
+	r, w := io.Pipe()
+	t.Cleanup(func() { w.Close() })
+	opts = append([]Option{WithClickEvents(), WithClickReplay(10, 0)}, opts...)
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		t.Fatal(err)
+	}
+	return s, w
+}
+
+func click(t *testing.T, w io.Writer, name string, x int) {
+	t.Helper()
+	if _, err := io.WriteString(w, `{"name":"`+name+`","button":1,"x":`+strconv.Itoa(x)+"}\n,"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClickReplay(t *testing.T) {
+	s, w := replayStream(t)
+
+	click(t, w, "a", 1)
+	click(t, w, "a", 2)
+	waitFor(t, "buffered events", func() bool { return s.ClickStats("a", "").Received == 2 })
+	if stats := s.ClickStats("a", ""); stats.Dispatched != 0 || stats.Dropped != 0 {
+		t.Fatalf("buffered events counted: %+v", stats)
+	}
+
+	var running int32
+	got := make(chan int, 3)
+	err := s.OnClick("a", "", func(ev ClickEvent) {
+		if atomic.AddInt32(&running, 1) != 1 {
+			t.Error("handler called concurrently")
+		}
+		time.Sleep(10 * time.Millisecond)
+		got <- ev.X
+		atomic.AddInt32(&running, -1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	click(t, w, "a", 3)
+
+	for want := 1; want <= 3; want++ {
+		select {
+		case x := <-got:
+			if x != want {
+				t.Errorf("got event %d, want %d", x, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not delivered", want)
+		}
+	}
+	waitFor(t, "handled events", func() bool { return s.ClickStats("a", "").Handled == 3 })
+	if stats := s.ClickStats("a", ""); stats.Dispatched != 3 || stats.Dropped != 0 {
+		t.Errorf("got %+v", stats)
+	}
+	select {
+	case x := <-got:
+		t.Errorf("event %d delivered twice", x)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClickReplayConsumed(t *testing.T) {
+	handled := make(chan ClickEvent, 1)
+	s, w := replayStream(t, WithClickHandler(func(ev ClickEvent) { handled <- ev }))
+
+	click(t, w, "a", 1)
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("click handler not called")
+	}
+
+	replayed := make(chan ClickEvent, 1)
+	if err := s.OnClick("a", "", func(ev ClickEvent) { replayed <- ev }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-replayed:
+		t.Errorf("consumed event replayed: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestClickReplayEvicted(t *testing.T) {
+	s, w := replayStream(t, WithClickReplay(1, 0))
+
+	click(t, w, "a", 1)
+	click(t, w, "a", 2)
+	waitFor(t, "evicted event", func() bool { return s.ClickStats("a", "").Dropped == 1 })
+
+	replayed := make(chan int, 2)
+	if err := s.OnClick("a", "", func(ev ClickEvent) { replayed <- ev.X }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case x := <-replayed:
+		if x != 2 {
+			t.Errorf("replayed event %d, want 2", x)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event not replayed")
+	}
+}
+
+func TestClickReplayEventsReader(t *testing.T) {
+	s, w := replayStream(t)
+	events := s.Events()
+
+	// the reader is busy while the events arrive
+	click(t, w, "a", 1)
+	click(t, w, "a", 2)
+	time.Sleep(20 * time.Millisecond)
+	for want := 1; want <= 2; want++ {
+		select {
+		case ev := <-events:
+			if ev.X != want {
+				t.Errorf("got event %d, want %d", ev.X, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not delivered to the Events channel", want)
+		}
+	}
+
+	replayed := make(chan ClickEvent, 2)
+	if err := s.OnClick("a", "", func(ev ClickEvent) { replayed <- ev }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-replayed:
+		t.Errorf("event received from Events replayed: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,208 @@
+package i3bar
+
+import (
+	"github.com/pkg/errors"
+)
+
+// clickRoute identifies the block a click handler is registered for.
+type clickRoute struct {
+	name     string
+	instance string
+}
+
+// OnClick registers handler to be called for click events on blocks
+// with the given name and instance. If instance is empty, handler
+// receives click events of all instances without their own handler.
+// A previously registered handler for the same block is replaced,
+// a nil handler removes it. Handlers registered by OnClick take
+// precedence over Block.OnClick.
+//
+// Routed click events are neither delivered to handlers registered by
+// WithClickHandler nor to the Events channel.
+//
+// Click events have to be enabled in the header, e.g. by WithClickEvents.
+// Otherwise ErrClickEventsDisabled is returned and handler is not registered.
+//
+// Click events buffered by WithClickReplay are dispatched to handler
+// by the click event reader in order with live events.
+//
+// This function is thread safe.
+func (s *Stream) OnClick(name, instance string, handler func(ev ClickEvent)) error {
+	route := clickRoute{name: name, instance: instance}
+	if handler == nil {
+		s.rMux.Lock()
+		delete(s.routes, route)
+		s.rMux.Unlock()
+		return nil
+	}
+	if !s.header.ClickEvents {
+		return ErrClickEventsDisabled
+	}
+
+	s.rMux.Lock()
+	if s.routes == nil {
+		s.routes = map[clickRoute]func(ClickEvent){}
+	}
+	s.routes[route] = handler
+	expired := s.queueReplay(route)
+	s.rMux.Unlock()
+
+	s.dropClicks(expired)
+	return nil
+}
+
+// ignoredModifiers are not considered when matching bindings,
+// since caps lock and num lock are usually toggled.
+const ignoredModifiers = Modifiers(Lock | Mod2)
+
+// binding is a click handler for a combination of button and modifiers.
+type binding struct {
+	button    Button
+	modifiers Modifiers
+	handler   func(ClickEvent)
+}
+
+// Bind registers handler to be called for click events on blocks with
+// the given name and instance if button is clicked while exactly the
+// given modifiers are held down, e.g. to open a mixer on Shift+click
+// while a plain click toggles mute. Lock and Mod2 (usually num lock)
+// are ignored. If instance is empty, handler receives click events of
+// all instances without their own bindings or handler.
+// A previously bound handler for the same combination is replaced,
+// a nil handler removes it.
+//
+// Bindings take precedence over handlers registered by OnClick for
+// the same block, which receive all click events not bound.
+//
+// Click events have to be enabled in the header, e.g. by WithClickEvents.
+// Otherwise ErrClickEventsDisabled is returned and handler is not bound.
+//
+// This function is thread safe.
+func (s *Stream) Bind(name, instance string, button Button, modifiers Modifiers, handler func(ev ClickEvent)) error {
+	if handler != nil && !s.header.ClickEvents {
+		return ErrClickEventsDisabled
+	}
+
+	s.rMux.Lock()
+	defer s.rMux.Unlock()
+	route := clickRoute{name: name, instance: instance}
+	modifiers &^= ignoredModifiers
+
+	bindings := s.bindings[route][:0:0]
+	for _, b := range s.bindings[route] {
+		if b.button != button || b.modifiers != modifiers {
+			bindings = append(bindings, b)
+		}
+	}
+	if handler != nil {
+		bindings = append(bindings, binding{button: button, modifiers: modifiers, handler: handler})
+	}
+
+	if s.bindings == nil {
+		s.bindings = map[clickRoute][]binding{}
+	}
+	if len(bindings) == 0 {
+		delete(s.bindings, route)
+		return nil
+	}
+	s.bindings[route] = bindings
+	return nil
+}
+
+// registerBlocks replaces the click handlers of blocks by the
+// OnClick handlers of the blocks of line.
+// The caller has to hold wMux.
+func (s *Stream) registerBlocks(line StatusLine) {
+	var routes map[clickRoute]func(ClickEvent)
+	for _, b := range line {
+		if b == nil || b.OnClick == nil {
+			continue
+		}
+		if routes == nil {
+			routes = map[clickRoute]func(ClickEvent){}
+		}
+		routes[clickRoute{name: b.Name, instance: b.Instance}] = b.OnClick
+	}
+	if routes != nil && !s.header.ClickEvents && !s.clicksReported {
+		// i3bar never sends click events for these blocks
+		s.clicksReported = true
+		s.reportError(errors.Wrap(ErrClickEventsDisabled, "Block.OnClick is never called"))
+	}
+
+	s.rMux.Lock()
+	s.blockRoutes = routes
+	var expired []ClickEvent
+	for route := range routes {
+		expired = append(expired, s.queueReplay(route)...)
+	}
+	s.rMux.Unlock()
+
+	s.dropClicks(expired)
+}
+
+// lookup returns the handler responsible for ev.
+// The caller has to hold rMux.
+func (s *Stream) lookup(ev ClickEvent) (func(ClickEvent), bool) {
+	modifiers := ev.Modifiers &^ ignoredModifiers
+	routes := []clickRoute{
+		{name: ev.Name, instance: ev.Instance},
+		{name: ev.Name},
+	}
+	for _, route := range routes {
+		for _, b := range s.bindings[route] {
+			if b.button == ev.Button && b.modifiers == modifiers {
+				return b.handler, true
+			}
+		}
+		if handler, ok := s.routes[route]; ok {
+			return handler, true
+		}
+	}
+	handler, ok := s.blockRoutes[routes[0]]
+	return handler, ok
+}
+
+// dispatch delivers ev to the handler bound by Bind or registered by
+// OnClick, the OnClick handler of its block, the handlers registered
+// by WithClickHandler or the Events channel and counts the outcome for
+// the block identified by key. Events reaching a handler are counted as
+// dispatched before the handler runs.
+// With WithClickReplay, events are kept for handlers registered later
+// instead if neither click handlers are registered nor Events was called.
+// Kept events are counted once they are replayed or dropped from the buffer.
+// ok is false if stop or done got closed before ev was delivered.
+func (s *Stream) dispatch(ev ClickEvent, key clickRoute, events chan<- ClickEvent, stop, done <-chan struct{}) (ok bool) {
+	s.rMux.Lock()
+	handler, found := s.lookup(ev)
+	if !found && len(s.clickHandlers) == 0 && s.replaySize > 0 && !s.eventsTaken {
+		dropped := s.record(ev)
+		s.rMux.Unlock()
+		s.dropClicks(dropped)
+		return true
+	}
+	s.rMux.Unlock()
+
+	if found {
+		s.countClick(key, ClickDispatched, 0)
+		s.runClick(key, func() { handler(ev) })
+		return true
+	}
+	if len(s.clickHandlers) > 0 {
+		s.countClick(key, ClickDispatched, 0)
+		s.runClick(key, func() {
+			for _, fn := range s.clickHandlers {
+				fn(ev)
+			}
+		})
+		return true
+	}
+	select {
+	case events <- ev:
+		s.countClick(key, ClickDispatched, 0)
+		return true
+	case <-stop:
+	case <-done:
+	}
+	s.countClick(key, ClickDropped, 0)
+	return false
+}
@@ -0,0 +1,269 @@
+package i3bar
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestClickHandlersRequireClickEvents(t *testing.T) {
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	handler := func(ClickEvent) {}
+	if err := s.OnClick("a", "", handler); !errors.Is(err, ErrClickEventsDisabled) {
+		t.Errorf("OnClick: got %v, want ErrClickEventsDisabled", err)
+	}
+	if err := s.Bind("a", "", LeftClick, 0, handler); !errors.Is(err, ErrClickEventsDisabled) {
+		t.Errorf("Bind: got %v, want ErrClickEventsDisabled", err)
+	}
+	if err := s.OnClick("a", "", nil); err != nil {
+		t.Errorf("removing handler: %v", err)
+	}
+
+	// Block.OnClick is reported once
+	line := StatusLine{{Name: "a", FullText: "a", OnClick: handler}}
+	for i := 0; i < 2; i++ {
+		if err := s.SendLine(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case err := <-s.Errors():
+		if !errors.Is(err, ErrClickEventsDisabled) {
+			t.Errorf("got %v, want ErrClickEventsDisabled", err)
+		}
+	default:
+		t.Error("Block.OnClick without click events was not reported")
+	}
+	select {
+	case err := <-s.Errors():
+		t.Errorf("reported again: %v", err)
+	default:
+	}
+}
+
+func TestWithClickEvents(t *testing.T) {
+	if _, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), WithClickEvents()); err == nil {
+		t.Error("WithClickEvents without reader succeeded")
+	}
+
+	r, w := io.Pipe()
+	defer w.Close()
+	var out bytes.Buffer
+	s, err := NewStream(&out, r, false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if !strings.HasPrefix(out.String(), `{"version":1,"click_events":true}`) {
+		t.Errorf("click events not enabled in header: %q", out.String())
+	}
+
+	clicked := make(chan ClickEvent, 1)
+	if err := s.OnClick("a", "", func(ev ClickEvent) { clicked <- ev }); err != nil {
+		t.Fatal(err)
+	}
+	go io.WriteString(w, "[\n{\"name\":\"a\",\"button\":1}\n")
+	select {
+	case ev := <-clicked:
+		if ev.Name != "a" || ev.Button != LeftClick {
+			t.Errorf("got %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+}
+
+func TestClickEventsHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header Header
+		opts   []Option
+		want   bool
+	}{
+		{"disabled", DefaultHeader(), nil, false},
+		{"header", Header{Version: 1, ClickEvents: true}, nil, true},
+		{"click handler", DefaultHeader(), []Option{WithClickHandler(func(ClickEvent) {})}, true},
+		{"click events", DefaultHeader(), []Option{WithClickEvents()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := probeOptions(tt.header, tt.opts).header.ClickEvents; got != tt.want {
+				t.Errorf("probed click events %v, want %v", got, tt.want)
+			}
+
+			r, w := io.Pipe()
+			defer w.Close()
+			var out bytes.Buffer
+			s, err := NewStream(&out, r, false, tt.header, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			if got := strings.Contains(out.String(), `"click_events":true`); got != tt.want {
+				t.Errorf("got header %q, want click events %v", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestBind(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	var got string
+	bind := func(name, instance string, button Button, modifiers Modifiers, tag string) {
+		t.Helper()
+		if err := s.Bind(name, instance, button, modifiers, func(ClickEvent) { got = tag }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bind("vol", "", LeftClick, 0, "click")
+	bind("vol", "", LeftClick, Modifiers(Shift), "shift")
+	bind("vol", "", LeftClick, Modifiers(Shift|Control), "shift+control")
+	bind("vol", "", RightClick, 0, "replaced")
+	bind("vol", "", RightClick, 0, "right")
+	bind("vol", "mic", LeftClick, 0, "mic")
+	bind("vol", "", MiddleClick, 0, "removed")
+	if err := s.Bind("vol", "", MiddleClick, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.OnClick("vol", "", func(ClickEvent) { got = "handler" }); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ev   ClickEvent
+		want string
+	}{
+		{"plain click", ClickEvent{Name: "vol", Button: LeftClick}, "click"},
+		{"shift click", ClickEvent{Name: "vol", Button: LeftClick, Modifiers: Modifiers(Shift)}, "shift"},
+		{"shift control click", ClickEvent{Name: "vol", Button: LeftClick, Modifiers: Modifiers(Control | Shift)}, "shift+control"},
+		// lock and num lock are ignored
+		{"num lock", ClickEvent{Name: "vol", Button: LeftClick, Modifiers: Modifiers(Mod2 | Lock)}, "click"},
+		{"shift num lock", ClickEvent{Name: "vol", Button: LeftClick, Modifiers: Modifiers(Shift | Mod2)}, "shift"},
+		// modifiers have to match exactly
+		{"unbound modifier", ClickEvent{Name: "vol", Button: LeftClick, Modifiers: Modifiers(Mod4)}, "handler"},
+		{"replaced binding", ClickEvent{Name: "vol", Button: RightClick}, "right"},
+		{"removed binding", ClickEvent{Name: "vol", Button: MiddleClick}, "handler"},
+		{"instance binding", ClickEvent{Name: "vol", Instance: "mic", Button: LeftClick}, "mic"},
+		// instances without own bindings use the bindings of the name
+		{"other instance", ClickEvent{Name: "vol", Instance: "speaker", Button: LeftClick, Modifiers: Modifiers(Shift)}, "shift"},
+		{"instance falls back", ClickEvent{Name: "vol", Instance: "mic", Button: RightClick}, "right"},
+		{"other block", ClickEvent{Name: "cpu", Button: LeftClick}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			s.rMux.Lock()
+			handler, ok := s.lookup(tt.ev)
+			s.rMux.Unlock()
+			if ok {
+				handler(tt.ev)
+			}
+			if got != tt.want {
+				t.Errorf("got handler %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindDispatch(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), WithClickEvents())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	got := make(chan string, 2)
+	s.Bind("vol", "", LeftClick, Modifiers(Shift), func(ClickEvent) { got <- "shift" })
+	s.Bind("vol", "", LeftClick, 0, func(ClickEvent) { got <- "click" })
+
+	io.WriteString(w, "[\n"+
+		`{"name":"vol","button":1,"modifiers":["Shift","Mod2"]}`+"\n"+
+		`,{"name":"vol","button":1,"modifiers":[]}`+"\n")
+	for _, want := range []string{"shift", "click"} {
+		select {
+		case tag := <-got:
+			if tag != want {
+				t.Errorf("got %q, want %q", tag, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("binding %q not called", want)
+		}
+	}
+}
+
+func TestBlockOnClick(t *testing.T) {
+	// all handlers report to got, each test expects a single call
+	got := make(chan string, 1)
+	tag := func(tag string) func(ClickEvent) {
+		return func(ClickEvent) { got <- tag }
+	}
+	block := func(instance string, handler func(ClickEvent)) StatusLine {
+		return StatusLine{{Name: "a", Instance: instance, FullText: "a", OnClick: handler}}
+	}
+
+	tests := []struct {
+		name   string
+		lines  []StatusLine
+		stream bool
+		want   string
+	}{
+		{"block handler", []StatusLine{block("", tag("block"))}, false, "block"},
+		{"stream handler first", []StatusLine{block("", tag("block"))}, true, "stream"},
+		{"other block", []StatusLine{{{Name: "b", FullText: "b", OnClick: tag("block")}}}, false, "fallback"},
+		// block handlers match the instance exactly
+		{"other instance", []StatusLine{block("0", tag("block"))}, false, "fallback"},
+		// only the blocks of the last status line are clickable
+		{"removed handler", []StatusLine{block("", tag("block")), block("", nil)}, false, "fallback"},
+		{"replaced handler", []StatusLine{block("", tag("old")), block("", tag("new"))}, false, "new"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := io.Pipe()
+			defer w.Close()
+			s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(), WithClickHandler(tag("fallback")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			if tt.stream {
+				if err := s.OnClick("a", "", tag("stream")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, line := range tt.lines {
+				if err := s.SendLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			io.WriteString(w, "[\n"+`{"name":"a","button":1}`+"\n")
+			select {
+			case handler := <-got:
+				if handler != tt.want {
+					t.Errorf("got %q, want %q", handler, tt.want)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no handler called")
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package i3bar
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Run calls fn right away and then every interval and sends the
+// produced status line until ctx is done or the stream is closed or disconnected.
+// fn is not called while the stream is paused, so no work is done
+// while the bar is hidden.
+// Errors of fn are reported on s.Errors() and the status line is skipped.
+// Run returns an error if a status line could not be sent for another
+// reason than the stream being closed or disconnected.
+func (s *Stream) Run(ctx context.Context, interval time.Duration, fn func(ctx context.Context) (StatusLine, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if !s.Paused() {
+			line, err := fn(ctx)
+			if err != nil && ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				s.reportError(errors.Wrap(err, "Failed to produce status line"))
+			} else if err := s.SendLine(line); err != nil {
+				if errors.Is(err, ErrClosed) || errors.Is(err, ErrDisconnected) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		case <-s.stopped():
+			return nil
+		}
+	}
+}
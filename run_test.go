@@ -0,0 +1,66 @@
+package i3bar
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		// stop is called by the second call of the update function
+		stop func(s *Stream, cancel func())
+		want error
+	}{
+		{"cancelled", func(s *Stream, cancel func()) { cancel() }, nil},
+		{"closed", func(s *Stream, cancel func()) { s.Close() }, nil},
+		{"disconnected", nil, nil},
+		{"invalid", func(s *Stream, cancel func()) {}, ErrEncode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var w io.Writer = &bytes.Buffer{}
+			var disconnect func()
+			if tt.stop == nil {
+				w, disconnect = brokenPipe(t)
+			}
+			s, err := NewStream(w, nil, false, DefaultHeader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			calls := 0
+			err = s.Run(ctx, time.Millisecond, func(context.Context) (StatusLine, error) {
+				calls++
+				if calls == 2 {
+					if disconnect != nil {
+						disconnect()
+					} else {
+						tt.stop(s, cancel)
+					}
+					if tt.want != nil {
+						return StatusLine{{FullText: "a", Color: "invalid"}}, nil
+					}
+				}
+				return StatusLine{{FullText: "a"}}, nil
+			})
+			if tt.want == nil && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+			if ctx.Err() == context.DeadlineExceeded {
+				t.Error("Run did not return")
+			}
+		})
+	}
+}
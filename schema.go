@@ -0,0 +1,34 @@
+package i3bar
+
+import _ "embed"
+
+// JSON Schemas (draft-07) describing exactly what this package
+// emits and accepts, so that other tools can validate their payloads.
+// They are generated from the Block, Header and ClickEvent types.
+//
+//go:generate go test -run ^TestSchemas$ -update
+var (
+	//go:embed schema/block.json
+	blockSchema []byte
+
+	//go:embed schema/header.json
+	headerSchema []byte
+
+	//go:embed schema/click_event.json
+	clickEventSchema []byte
+)
+
+// BlockSchema returns the JSON Schema of an encoded Block.
+func BlockSchema() []byte {
+	return append([]byte(nil), blockSchema...)
+}
+
+// HeaderSchema returns the JSON Schema of an encoded Header.
+func HeaderSchema() []byte {
+	return append([]byte(nil), headerSchema...)
+}
+
+// ClickEventSchema returns the JSON Schema of a ClickEvent sent by i3bar.
+func ClickEventSchema() []byte {
+	return append([]byte(nil), clickEventSchema...)
+}
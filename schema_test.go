@@ -0,0 +1,422 @@
+package i3bar
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update generated schemas and golden files")
+
+// schemaObject is a json object keeping the order of its keys.
+type schemaObject []schemaMember
+
+// schemaMember is a single key of a schemaObject.
+type schemaMember struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON encodes the members in their order.
+func (o schemaObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(m.key)
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// schemaConstraints mirror the checks of Header.validate and Block.validate,
+// keyed by the type name and the json key of the field.
+var schemaConstraints = map[string]schemaObject{
+	"Header.version":              {{"minimum", 1}},
+	"Header.stop_signal":          {{"minimum", 0}, {"maximum", maxSignal}, {"not", schemaObject{{"const", int(syscall.SIGKILL)}}}},
+	"Header.cont_signal":          {{"minimum", 0}, {"maximum", maxSignal}, {"not", schemaObject{{"enum", []int{int(syscall.SIGKILL), int(syscall.SIGSTOP)}}}}},
+	"Block.border_top":            {{"minimum", 0}},
+	"Block.border_right":          {{"minimum", 0}},
+	"Block.border_bottom":         {{"minimum", 0}},
+	"Block.border_left":           {{"minimum", 0}},
+	"Block.separator_block_width": {{"minimum", 0}},
+}
+
+// generatedSchemas lists the schemas in schema/ and the types they describe.
+// Required keys are only derived for types this package emits, since
+// decoding click events does not require any key.
+var generatedSchemas = []struct {
+	file    string
+	v       interface{}
+	emitted bool
+}{
+	{"block.json", Block{}, true},
+	{"header.json", Header{}, true},
+	{"click_event.json", ClickEvent{}, false},
+}
+
+// TestSchemas checks that the schemas in schema/ match the types they describe.
+// Run go generate to update them.
+func TestSchemas(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("schema/ contains the signal numbers of linux")
+	}
+	docs := typeDocs(t)
+	for _, gen := range generatedSchemas {
+		t.Run(gen.file, func(t *testing.T) {
+			data, err := json.MarshalIndent(generateSchema(docs, reflect.TypeOf(gen.v), gen.file, gen.emitted), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data = append(data, '\n')
+
+			path := filepath.Join("schema", gen.file)
+			if *update {
+				if err := os.WriteFile(path, data, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(data, want) {
+				t.Errorf("%s is out of date, run go generate", path)
+			}
+		})
+	}
+}
+
+// typeDocs collects the doc comments of all types and struct fields
+// of the package, keyed by "Type" and "Type.Field".
+func typeDocs(t *testing.T) map[string]string {
+	t.Helper()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs := map[string]string{}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				docs[ts.Name.Name] = docText(gen.Doc)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					for _, name := range field.Names {
+						docs[ts.Name.Name+"."+name.Name] = docText(field.Doc)
+					}
+				}
+			}
+		}
+	}
+	return docs
+}
+
+// docText joins the lines of a doc comment.
+func docText(doc *ast.CommentGroup) string {
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// generateSchema creates the JSON Schema of the struct type t.
+func generateSchema(docs map[string]string, t reflect.Type, file string, emitted bool) schemaObject {
+	schema := schemaObject{
+		{"$schema", "http://json-schema.org/draft-07/schema#"},
+		{"$id", "https://github.com/g0dsCookie/go-i3bar/schema/" + file},
+		{"title", t.Name()},
+		{"description", docs[t.Name()]},
+		{"type", "object"},
+	}
+
+	var properties schemaObject
+	required := []string{}
+	usesColor := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "" || tag[0] == "-" {
+			continue
+		}
+		if emitted && !strings.Contains(field.Tag.Get("json"), ",omitempty") {
+			required = append(required, tag[0])
+		}
+		usesColor = usesColor || field.Type == reflect.TypeOf(Color(""))
+
+		property := schemaObject{{"description", docs[t.Name()+"."+field.Name]}}
+		property = append(property, typeSchema(field.Type)...)
+		property = append(property, schemaConstraints[t.Name()+"."+tag[0]]...)
+		properties = append(properties, schemaMember{tag[0], property})
+	}
+
+	if usesColor {
+		// colors are normalized by Color.MarshalText
+		schema = append(schema, schemaMember{"definitions", schemaObject{
+			{"color", schemaObject{
+				{"type", "string"},
+				{"pattern", "^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$"},
+			}},
+		}})
+	}
+	schema = append(schema, schemaMember{"properties", properties})
+	if len(required) > 0 {
+		schema = append(schema, schemaMember{"required", required})
+	}
+	return schema
+}
+
+// typeSchema returns the schema keywords describing how values of t are encoded.
+func typeSchema(t reflect.Type) schemaObject {
+	switch t {
+	case reflect.TypeOf(Color("")):
+		return schemaObject{{"$ref", "#/definitions/color"}}
+	case reflect.TypeOf(&MinWidth{}):
+		// pixels are validated to be not negative
+		return schemaObject{{"oneOf", []schemaObject{
+			{{"type", "integer"}, {"minimum", 0}},
+			{{"type", "string"}},
+		}}}
+	case reflect.TypeOf(Button(0)):
+		// Button.UnmarshalJSON accepts the button names as well
+		return schemaObject{{"oneOf", []schemaObject{
+			{{"type", "integer"}},
+			{{"enum", enumNames(t)}},
+		}}}
+	case reflect.TypeOf(Modifiers(0)):
+		// unknown modifiers are ignored by Modifiers.UnmarshalJSON
+		return schemaObject{{"type", "array"}, {"items", schemaObject{{"type", "string"}}}}
+	}
+
+	if t.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()) {
+		return schemaObject{{"enum", enumNames(t)}}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return schemaObject{{"type", "string"}}
+	case reflect.Bool:
+		return schemaObject{{"type", "boolean"}}
+	case reflect.Int:
+		return schemaObject{{"type", "integer"}}
+	}
+	panic("no schema for type " + t.String())
+}
+
+// enumNames returns the names of all values of the integer type t
+// which can be encoded by its MarshalText.
+func enumNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < 64; i++ {
+		v := reflect.New(t).Elem()
+		v.SetInt(int64(i))
+		if text, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			names = append(names, string(text))
+		}
+	}
+	return names
+}
+
+func TestSchemasMatchPayloads(t *testing.T) {
+	full := &Block{
+		Name:                "cpu",
+		Instance:            "0",
+		FullText:            "<b>42%</b>",
+		ShortText:           "42",
+		Color:               "#ff0000",
+		Background:          "#00ff0080",
+		Border:              "steelblue",
+		BorderTop:           Int(0),
+		BorderRight:         Int(1),
+		BorderBottom:        Int(2),
+		BorderLeft:          Int(3),
+		MinWidth:            MinWidthText("100%"),
+		Align:               Right,
+		Urgent:              true,
+		Separator:           Bool(false),
+		SeparatorBlockWidth: 9,
+		Markup:              Pango,
+		Extra:               map[string]interface{}{"_vendor": 1},
+	}
+
+	tests := []struct {
+		name    string
+		schema  []byte
+		payload interface{}
+		valid   bool
+	}{
+		// payloads emitted by this package
+		{"full block", BlockSchema(), full, true},
+		{"empty block", BlockSchema(), &Block{}, true},
+		{"pixel min width", BlockSchema(), &Block{FullText: "a", MinWidth: MinWidthPixels(30)}, true},
+		{"default header", HeaderSchema(), DefaultHeader(), true},
+		{"custom signals", HeaderSchema(), Header{Version: 1, StopSignal: int(syscall.SIGUSR1), ContSignal: int(syscall.SIGUSR2), ClickEvents: true}, true},
+		{"default signals", HeaderSchema(), Header{Version: 1, StopSignal: int(syscall.SIGSTOP), ContSignal: int(syscall.SIGCONT)}, true},
+		{"unknown version", HeaderSchema(), Header{Version: 2}, true},
+
+		// payloads accepted by this package
+		{"i3bar click", ClickEventSchema(), json.RawMessage(`{"name":"cpu","instance":"0","button":1,"modifiers":["Shift","Mod2"],"x":1800,"y":10,"relative_x":12,"relative_y":8,"output_x":1800,"output_y":10,"width":60,"height":20}`), true},
+		{"old i3bar click", ClickEventSchema(), json.RawMessage(`{"name":"cpu","button":5,"x":1800,"y":10}`), true},
+		{"button name", ClickEventSchema(), json.RawMessage(`{"name":"cpu","button":"scroll_up"}`), true},
+		{"unknown modifier", ClickEventSchema(), json.RawMessage(`{"button":1,"modifiers":["Hyper"]}`), true},
+
+		// payloads rejected by this package
+		{"negative border", BlockSchema(), json.RawMessage(`{"full_text":"a","border_top":-1}`), false},
+		{"unknown align", BlockSchema(), json.RawMessage(`{"full_text":"a","align":"justify"}`), false},
+		{"uncatchable stop signal", HeaderSchema(), json.RawMessage(`{"version":1,"stop_signal":9}`), false},
+		{"uncatchable cont signal", HeaderSchema(), json.RawMessage(`{"version":1,"cont_signal":19}`), false},
+		{"invalid version", HeaderSchema(), json.RawMessage(`{"version":0}`), false},
+		{"unknown button", ClickEventSchema(), json.RawMessage(`{"button":"wheel"}`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema map[string]interface{}
+			if err := json.Unmarshal(tt.schema, &schema); err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(tt.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var v interface{}
+			if err := json.Unmarshal(data, &v); err != nil {
+				t.Fatal(err)
+			}
+			err = validateSchema(schema, schema, v)
+			if tt.valid && err != nil {
+				t.Errorf("%s: %v", data, err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("%s: accepted by schema", data)
+			}
+		})
+	}
+}
+
+// validateSchema validates v against the subset of JSON Schema used in schema/.
+func validateSchema(root, schema map[string]interface{}, v interface{}) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["definitions"].(map[string]interface{})[strings.TrimPrefix(ref, "#/definitions/")]
+		return validateSchema(root, def.(map[string]interface{}), v)
+	}
+	if typ, ok := schema["type"].(string); ok {
+		var valid bool
+		switch typ {
+		case "object":
+			_, valid = v.(map[string]interface{})
+		case "array":
+			_, valid = v.([]interface{})
+		case "string":
+			_, valid = v.(string)
+		case "boolean":
+			_, valid = v.(bool)
+		case "integer":
+			n, ok := v.(float64)
+			valid = ok && n == math.Trunc(n)
+		}
+		if !valid {
+			return fmt.Errorf("%v is not of type %s", v, typ)
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, v) {
+		return fmt.Errorf("%v is not %v", v, c)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			found = found || reflect.DeepEqual(e, v)
+		}
+		if !found {
+			return fmt.Errorf("%v is not one of %v", v, enum)
+		}
+	}
+	if n, ok := v.(float64); ok {
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%v is less than %v", n, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			return fmt.Errorf("%v is greater than %v", n, max)
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if s, _ := v.(string); !regexp.MustCompile(pattern).MatchString(s) {
+			return fmt.Errorf("%q does not match %s", s, pattern)
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range oneOf {
+			if validateSchema(root, sub.(map[string]interface{}), v) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%v matches %d schemas of oneOf", v, matches)
+		}
+	}
+	if not, ok := schema["not"].(map[string]interface{}); ok && validateSchema(root, not, v) == nil {
+		return fmt.Errorf("%v matches forbidden schema", v)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for _, item := range v.([]interface{}) {
+			if err := validateSchema(root, items, item); err != nil {
+				return err
+			}
+		}
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		required, _ := schema["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				return fmt.Errorf("missing required key %s", key)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, value := range obj {
+			if property, ok := properties[key].(map[string]interface{}); ok {
+				if err := validateSchema(root, property, value); err != nil {
+					return fmt.Errorf("%s: %v", key, err)
+				}
+			}
+		}
+	}
+	return nil
+}
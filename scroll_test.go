@@ -0,0 +1,89 @@
+package i3bar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrollDebounce(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		clicks string
+		want   string
+	}{
+		{"disabled", 0, "a4 a4 a4", "a4x0, a4x0, a4x0"},
+		{"single", time.Hour, "a4", "a4x1"},
+		{"coalesced", time.Hour, "a4 a4 a4", "a4x3"},
+		{"direction change", time.Hour, "a4 a4 a5 a4", "a4x2, a5x1, a4x1"},
+		{"other block", time.Hour, "a4 b4 a4", "a4x1, b4x1, a4x1"},
+		// other clicks are delivered after the pending scroll events
+		{"click in between", time.Hour, "a4 a4 a1 a4", "a4x2, a1x0, a4x1"},
+		{"horizontal", time.Hour, "a6 a6 a7", "a6x2, a7x1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stream bytes.Buffer
+			stream.WriteString("[\n")
+			for _, c := range strings.Fields(tt.clicks) {
+				fmt.Fprintf(&stream, "{\"name\":%q,\"button\":%c},\n", c[:1], c[1])
+			}
+			s, err := NewStream(&bytes.Buffer{}, &stream, false, DefaultHeader(),
+				WithClickEvents(), WithScrollDebounce(tt.window))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			// pending scroll events are delivered once the stream ends
+			var got []string
+			for ev := range s.Events() {
+				got = append(got, fmt.Sprintf("%s%dx%d", ev.Name, ev.Button, ev.Delta))
+			}
+			if strings.Join(got, ", ") != tt.want {
+				t.Errorf("got %q, want %q", strings.Join(got, ", "), tt.want)
+			}
+		})
+	}
+}
+
+func TestScrollDebounceWindow(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	window := 20 * time.Millisecond
+	s, err := NewStream(&bytes.Buffer{}, r, false, DefaultHeader(),
+		WithClickEvents(), WithScrollDebounce(window))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	start := time.Now()
+	io.WriteString(w, "[\n{\"name\":\"a\",\"button\":4}\n,{\"name\":\"a\",\"button\":4}\n")
+	select {
+	case ev := <-s.Events():
+		if ev.Delta != 2 {
+			t.Errorf("got delta %d, want 2", ev.Delta)
+		}
+		if elapsed := time.Since(start); elapsed < window {
+			t.Errorf("scroll event delivered after %v, want at least %v", elapsed, window)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("coalesced scroll event not delivered after the window")
+	}
+
+	// the window starts again with the next scroll event
+	io.WriteString(w, ",{\"name\":\"a\",\"button\":4}\n")
+	select {
+	case ev := <-s.Events():
+		if ev.Delta != 1 {
+			t.Errorf("got delta %d, want 1", ev.Delta)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("scroll event not delivered after the window")
+	}
+}
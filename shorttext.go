@@ -0,0 +1,100 @@
+package i3bar
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ShortTextStrategy derives a short text from the full text of a block.
+// It returns an empty string if it is unable to shorten the text.
+type ShortTextStrategy func(full string) string
+
+// unitSuffix matches a number followed by one of the known units,
+// e.g. "42 °C" or "1.5GiB/s", and the character following the unit,
+// so that words like "1 day" are kept.
+var unitSuffix = regexp.MustCompile(`\b(\d+(?:[.,]\d+)?)\s*` +
+	`(?:%|°[CF]?|[kKMGTP]i?B(?:/s)?|B(?:/s)?|[kMG]?bit/s|[kMG]?Hz|[mk]?Wh?|[mk]?V|m?Ah?|RPM|rpm|dB|ms)` +
+	`(\PL|$)`)
+
+// ShortTruncate returns a strategy truncating the text to n graphemes
+// including DefaultEllipsis, see Truncate.
+func ShortTruncate(n int) ShortTextStrategy {
+	return func(full string) string {
+		return Truncate(full, n)
+	}
+}
+
+// ShortStripUnits removes the units following numbers, e.g. "CPU 42 °C"
+// becomes "CPU 42". Only common units of percentages, temperatures,
+// sizes, rates, frequencies and electrical values are removed.
+func ShortStripUnits(full string) string {
+	return strings.Join(strings.Fields(unitSuffix.ReplaceAllString(full, "$1$2")), " ")
+}
+
+// ShortFirstWord keeps the first word of the text only.
+func ShortFirstWord(full string) string {
+	words := strings.Fields(full)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[0]
+}
+
+// ShortIconOnly keeps the leading icons of the text only, which are
+// symbols or glyphs from the private use area like Font Awesome icons.
+func ShortIconOnly(full string) string {
+	full = strings.TrimLeftFunc(full, unicode.IsSpace)
+	end := 0
+	for _, g := range Graphemes(full) {
+		r := []rune(g)[0]
+		if !unicode.In(r, unicode.Co, unicode.So) {
+			break
+		}
+		end += len(g)
+	}
+	return full[:end]
+}
+
+// DeriveShortText sets the ShortText of b from its text using the first
+// strategy yielding a text shorter than the full text, unless b already
+// has a ShortText. Pango markup is removed before the strategies are
+// applied and the result is escaped again.
+func (b *Block) DeriveShortText(strategies ...ShortTextStrategy) {
+	if b.ShortText != "" {
+		return
+	}
+	full := b.Text()
+	if b.Markup == Pango {
+		full = html.UnescapeString(anyTag.ReplaceAllString(full, ""))
+	}
+	n := GraphemeCount(full)
+	for _, strategy := range strategies {
+		short := strategy(full)
+		if short == "" || GraphemeCount(short) >= n {
+			continue
+		}
+		if b.Markup == Pango {
+			short = PangoEscape(short)
+		}
+		b.ShortText = short
+		return
+	}
+}
+
+// AutoShortText returns a Middleware deriving the ShortText of all blocks
+// without one from their full text, see Block.DeriveShortText.
+// Strategies are tried in order, e.g.
+//
+//	AutoShortText(ShortStripUnits, ShortIconOnly, ShortTruncate(8))
+func AutoShortText(strategies ...ShortTextStrategy) Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			if b != nil {
+				b.DeriveShortText(strategies...)
+			}
+		}
+		return line
+	}
+}
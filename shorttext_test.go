@@ -0,0 +1,39 @@
+package i3bar
+
+import "testing"
+
+func TestShortStripUnits(t *testing.T) {
+	tests := []struct {
+		full string
+		want string
+	}{
+		{"CPU 42%", "CPU 42"},
+		{"CPU 42 %", "CPU 42"},
+		{"CPU 42 °C", "CPU 42"},
+		{"CPU 42°F", "CPU 42"},
+		{"up 1.5GiB/s down 20 KiB/s", "up 1.5 down 20"},
+		{"mem 3,2 GB", "mem 3,2"},
+		{"disk 512B", "disk 512"},
+		{"net 100 Mbit/s", "net 100"},
+		{"cpu 2.4GHz", "cpu 2.4"},
+		{"bat 12.5 W 11.1V 3000 mAh", "bat 12.5 11.1 3000"},
+		{"fan 1200 RPM", "fan 1200"},
+		{"vol 80%, mic 0%", "vol 80, mic 0"},
+		{"ping 12ms", "ping 12"},
+		// words following numbers are no units
+		{"1 day left", "1 day left"},
+		{"3 updates", "3 updates"},
+		{"2 Bytes", "2 Bytes"},
+		{"10 Watt", "10 Watt"},
+		{"5 minutes", "5 minutes"},
+		// units need a number in front of them
+		{"GiB 42", "GiB 42"},
+		{"v2", "v2"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ShortStripUnits(tt.full); got != tt.want {
+			t.Errorf("ShortStripUnits(%q) = %q, want %q", tt.full, got, tt.want)
+		}
+	}
+}
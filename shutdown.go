@@ -0,0 +1,50 @@
+package i3bar
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Shutdown gracefully stops the stream. The click event reader is stopped,
+// in-flight writes are waited for and the farewell status line configured
+// by WithFarewell is sent instead of any pending status line before the
+// infinite json array is closed. Without a farewell status line Shutdown
+// behaves like Close.
+//
+// If ctx is done before the stream is shut down, e.g. because the writer
+// blocks, Shutdown returns the error of ctx. The shutdown continues in
+// the background and the stream is unusable nonetheless.
+// This function is thread safe.
+func (s *Stream) Shutdown(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.close(s.farewell)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "Failed to shut down stream in time")
+	}
+}
+
+// sendFarewell discards all pending status lines and sends farewell.
+// The caller has to hold wMux.
+func (s *Stream) sendFarewell(farewell StatusLine) error {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.pending, s.pendingBlink = nil, false
+	s.aMux.Lock()
+	s.latest = nil
+	s.aMux.Unlock()
+
+	b, err := s.prepare(farewell)
+	if err != nil {
+		return err
+	}
+	return s.encodeLine(b)
+}
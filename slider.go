@@ -0,0 +1,135 @@
+package i3bar
+
+import (
+	"math"
+	"strings"
+)
+
+// DefaultSliderWidth is the width of a Slider in characters
+// if no width is set.
+const DefaultSliderWidth = 10
+
+// Slider is a block rendering a value within a range as bar,
+// e.g. for volume or brightness. Clicking on the bar sets the value
+// to the clicked position, scrolling adjusts it by step.
+type Slider struct {
+	// Name of the slider block.
+	Name string
+
+	// Instance of the slider block.
+	Instance string
+
+	// Label is shown in front of the bar.
+	Label string
+
+	// Metrics measures the label in pixels if set, so that clicks are
+	// mapped onto the bar only. Otherwise the label and the bar are
+	// assumed to be rendered in a monospace font.
+	Metrics *FontMetrics
+
+	// Width of the bar in characters. DefaultSliderWidth is used if not set.
+	Width int
+
+	// Filled and Empty are the characters used to render the bar.
+	// Defaults to "█" and "░".
+	Filled, Empty string
+
+	min, max int
+	adjuster *ScrollAdjuster
+}
+
+// NewSlider creates a Slider for value within min and max.
+// onChange is called with the new value whenever the value
+// got changed by clicking or scrolling.
+func NewSlider(name string, value, step, min, max int, onChange func(value int)) *Slider {
+	return &Slider{
+		Name:     name,
+		min:      min,
+		max:      max,
+		adjuster: NewScrollAdjuster(value, step, min, max, onChange),
+	}
+}
+
+// Value returns the current value.
+func (s *Slider) Value() int {
+	return s.adjuster.Value()
+}
+
+// Set updates the value without calling onChange,
+// e.g. if the value got changed by another program.
+func (s *Slider) Set(value int) {
+	s.adjuster.Set(value)
+}
+
+// Handle sets the value to the clicked position on the bar on left clicks
+// and adjusts it on scroll events. All other click events are ignored.
+func (s *Slider) Handle(ev ClickEvent) {
+	if ev.Button != LeftClick {
+		s.adjuster.Handle(ev)
+		return
+	}
+	f, ok := s.barFraction(ev)
+	if !ok {
+		return
+	}
+	s.adjuster.update(func(int) int {
+		return s.min + int(math.Round(f*float64(s.max-s.min)))
+	})
+}
+
+// barFraction returns the position of the click within the bar from 0.0
+// at its left to 1.0 at its right end, leaving out the label.
+func (s *Slider) barFraction(ev ClickEvent) (float64, bool) {
+	f, ok := ev.Fraction()
+	if !ok || s.Label == "" {
+		return f, ok
+	}
+	label := s.Label + " "
+	var offset float64
+	if s.Metrics != nil {
+		offset = float64(s.Metrics.Width(label)) / float64(ev.Width)
+	} else {
+		n := GraphemeCount(label)
+		offset = float64(n) / float64(n+s.width())
+	}
+	if offset >= 1 {
+		return 0, false
+	}
+	return math.Max(0, math.Min(1, (f-offset)/(1-offset))), true
+}
+
+// Block renders the slider. The block handles its own click events,
+// see Block.OnClick.
+func (s *Slider) Block() *Block {
+	width := s.width()
+	filled, empty := s.Filled, s.Empty
+	if filled == "" {
+		filled = "█"
+	}
+	if empty == "" {
+		empty = "░"
+	}
+
+	var n int
+	if s.max > s.min {
+		n = int(math.Round(float64(s.Value()-s.min) / float64(s.max-s.min) * float64(width)))
+	}
+	text := strings.Repeat(filled, n) + strings.Repeat(empty, width-n)
+	if s.Label != "" {
+		text = s.Label + " " + text
+	}
+	return &Block{
+		Name:     s.Name,
+		Instance: s.Instance,
+		FullText: text,
+		OnClick:  s.Handle,
+	}
+}
+
+// width returns the width of the bar in characters.
+func (s *Slider) width() int {
+	if s.Width <= 0 {
+		return DefaultSliderWidth
+	}
+	return s.Width
+}
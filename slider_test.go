@@ -0,0 +1,51 @@
+package i3bar
+
+import "testing"
+
+func TestSliderClick(t *testing.T) {
+	// W is three times as wide as all other glyphs
+	proportional := &FontMetrics{
+		Size:       10,
+		unitsPerEm: 1000,
+		advances:   []uint16{500, 1500},
+		glyph: func(r rune) uint16 {
+			if r == 'W' {
+				return 1
+			}
+			return 0
+		},
+	}
+
+	tests := []struct {
+		name    string
+		label   string
+		metrics *FontMetrics
+		x       int
+		width   int
+		want    int
+	}{
+		{"no label", "", nil, 50, 100, 50},
+		{"no label right border", "", nil, 100, 100, 100},
+		// "vol " takes 4 of 14 characters
+		{"label start of bar", "vol", nil, 40, 140, 0},
+		{"label middle of bar", "vol", nil, 90, 140, 50},
+		{"label end of bar", "vol", nil, 140, 140, 100},
+		{"click on label", "vol", nil, 10, 140, 0},
+		// "WW " is 35 pixels wide
+		{"metrics middle of bar", "WW", proportional, 85, 135, 50},
+		{"metrics click on label", "WW", proportional, 20, 135, 0},
+		{"monospace metrics", "vol", monospace, 45, 70, 50},
+		{"no width", "vol", nil, 90, 0, 30},
+		{"label wider than block", "WW", proportional, 20, 30, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSlider("vol", 30, 5, 0, 100, nil)
+			s.Label, s.Metrics = tt.label, tt.metrics
+			s.Handle(ClickEvent{Name: "vol", Button: LeftClick, RelativeX: tt.x, Width: tt.width})
+			if got := s.Value(); got != tt.want {
+				t.Errorf("Value() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package i3bar
+
+import "sync"
+
+// Snapshot is an immutable StatusLine.
+// Modifications return a modified copy which shares all unchanged
+// blocks with the original, so snapshots can be passed between
+// goroutines without copying every block.
+// The zero value is an empty Snapshot.
+type Snapshot struct {
+	blocks StatusLine
+}
+
+// NewSnapshot creates a Snapshot of a deep copy of line.
+func NewSnapshot(line StatusLine) Snapshot {
+	return Snapshot{blocks: line.Clone()}
+}
+
+// Len returns the amount of blocks in the Snapshot.
+func (s Snapshot) Len() int {
+	return len(s.blocks)
+}
+
+// Get returns a copy of the block identified by name and instance
+// or nil if there is no such block.
+func (s Snapshot) Get(name, instance string) *Block {
+	return s.blocks.Find(name, instance).Clone()
+}
+
+// With returns a Snapshot where the block identified by the name and
+// instance of b is replaced by a copy of b. If there is no such block,
+// the copy is appended.
+func (s Snapshot) With(b *Block) Snapshot {
+	if b == nil {
+		return s
+	}
+	line := make(StatusLine, len(s.blocks), len(s.blocks)+1)
+	copy(line, s.blocks)
+	if !line.Replace(b.Name, b.Instance, b.Clone()) {
+		line = append(line, b.Clone())
+	}
+	return Snapshot{blocks: line}
+}
+
+// Without returns a Snapshot without the block identified by name and instance.
+func (s Snapshot) Without(name, instance string) Snapshot {
+	i := s.blocks.Index(name, instance)
+	if i < 0 {
+		return s
+	}
+	line := make(StatusLine, 0, len(s.blocks)-1)
+	line = append(line, s.blocks[:i]...)
+	line = append(line, s.blocks[i+1:]...)
+	return Snapshot{blocks: line}
+}
+
+// Line returns the StatusLine of the Snapshot for sending.
+// The returned slice is a copy but its blocks are shared with
+// the Snapshot and must not be modified.
+func (s Snapshot) Line() StatusLine {
+	return append(StatusLine(nil), s.blocks...)
+}
+
+// SharedSnapshot holds a Snapshot which is updated by concurrent modules.
+// The zero value holds an empty Snapshot.
+type SharedSnapshot struct {
+	mux  sync.Mutex
+	snap Snapshot
+}
+
+// Load returns the current Snapshot.
+func (s *SharedSnapshot) Load() Snapshot {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.snap
+}
+
+// Store replaces the current Snapshot.
+func (s *SharedSnapshot) Store(snap Snapshot) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.snap = snap
+}
+
+// Update atomically replaces the current Snapshot by the result of fn
+// and returns the new Snapshot.
+func (s *SharedSnapshot) Update(fn func(Snapshot) Snapshot) Snapshot {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.snap = fn(s.snap)
+	return s.snap
+}
@@ -0,0 +1,92 @@
+package i3bar
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	base := NewSnapshot(StatusLine{{Name: "a", FullText: "1"}, {Name: "b", FullText: "2"}})
+
+	tests := []struct {
+		name  string
+		snap  Snapshot
+		names []string
+	}{
+		{"zero", Snapshot{}, nil},
+		{"new", base, []string{"a/", "b/"}},
+		{"with existing", base.With(&Block{Name: "a", FullText: "3"}), []string{"a/", "b/"}},
+		{"with new", base.With(&Block{Name: "c"}), []string{"a/", "b/", "c/"}},
+		{"with nil", base.With(nil), []string{"a/", "b/"}},
+		{"without", base.Without("a", ""), []string{"b/"}},
+		{"without missing", base.Without("c", ""), []string{"a/", "b/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(tt.snap.Line()); !reflect.DeepEqual(got, tt.names) {
+				t.Errorf("got blocks %q, want %q", got, tt.names)
+			}
+			if tt.snap.Len() != len(tt.names) {
+				t.Errorf("Len() = %d, want %d", tt.snap.Len(), len(tt.names))
+			}
+		})
+	}
+
+	// modifications never change the original snapshot
+	if got := base.Get("a", "").FullText; got != "1" {
+		t.Errorf("original snapshot changed to %q", got)
+	}
+	if got := base.With(&Block{Name: "a", FullText: "3"}).Get("a", "").FullText; got != "3" {
+		t.Errorf("got %q, want %q", got, "3")
+	}
+	if base.Get("c", "") != nil {
+		t.Error("got missing block")
+	}
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	line := StatusLine{{Name: "a", FullText: "1"}}
+	b := &Block{Name: "b", FullText: "2"}
+	snap := NewSnapshot(line).With(b)
+
+	// the snapshot holds copies of the passed blocks
+	line[0].FullText = "changed"
+	b.FullText = "changed"
+	// blocks returned by Get are copies
+	snap.Get("a", "").FullText = "changed"
+	// the slice returned by Line is a copy
+	snap.Line()[0] = &Block{Name: "c"}
+
+	if got := []string{snap.Get("a", "").FullText, snap.Get("b", "").FullText}; !reflect.DeepEqual(got, []string{"1", "2"}) {
+		t.Errorf("snapshot changed to %q", got)
+	}
+}
+
+func TestSharedSnapshot(t *testing.T) {
+	var shared SharedSnapshot
+	if shared.Load().Len() != 0 {
+		t.Fatal("zero value is not empty")
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			shared.Update(func(s Snapshot) Snapshot {
+				return s.With(&Block{Name: name})
+			})
+		}(name)
+	}
+	wg.Wait()
+	// no concurrent update got lost
+	if got := shared.Load().Len(); got != 4 {
+		t.Errorf("got %d blocks, want 4", got)
+	}
+
+	shared.Store(Snapshot{})
+	if shared.Load().Len() != 0 {
+		t.Error("Store did not replace the snapshot")
+	}
+}
@@ -0,0 +1,91 @@
+package i3bar
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SpanBuilder composes a pango span and takes care of escaping
+// and the attribute syntax. Create one with Span.
+type SpanBuilder struct {
+	attrs []string
+}
+
+// Span starts building a pango span, e.g.
+//
+//	Span().Bold().Foreground(c).Size("small").Text(s)
+func Span() *SpanBuilder {
+	return &SpanBuilder{}
+}
+
+// Attr sets the span attribute name to value.
+func (sb *SpanBuilder) Attr(name, value string) *SpanBuilder {
+	sb.attrs = append(sb.attrs, name+`="`+PangoEscape(value)+`"`)
+	return sb
+}
+
+// Foreground sets the text color.
+func (sb *SpanBuilder) Foreground(c Color) *SpanBuilder {
+	return sb.Attr("foreground", string(c))
+}
+
+// Background sets the background color.
+func (sb *SpanBuilder) Background(c Color) *SpanBuilder {
+	return sb.Attr("background", string(c))
+}
+
+// Font sets the font description, e.g. "Monospace 10".
+func (sb *SpanBuilder) Font(desc string) *SpanBuilder {
+	return sb.Attr("font", desc)
+}
+
+// Size sets the font size, e.g. "small", "x-large" or "10240".
+func (sb *SpanBuilder) Size(size string) *SpanBuilder {
+	return sb.Attr("size", size)
+}
+
+// Weight sets the font weight, e.g. "bold" or "light".
+func (sb *SpanBuilder) Weight(weight string) *SpanBuilder {
+	return sb.Attr("weight", weight)
+}
+
+// Bold sets the font weight to bold.
+func (sb *SpanBuilder) Bold() *SpanBuilder {
+	return sb.Weight("bold")
+}
+
+// Italic sets the font style to italic.
+func (sb *SpanBuilder) Italic() *SpanBuilder {
+	return sb.Attr("style", "italic")
+}
+
+// Underline underlines the text with a single line.
+func (sb *SpanBuilder) Underline() *SpanBuilder {
+	return sb.Attr("underline", "single")
+}
+
+// Strikethrough strikes the text through.
+func (sb *SpanBuilder) Strikethrough() *SpanBuilder {
+	return sb.Attr("strikethrough", "true")
+}
+
+// Rise moves the text vertically by units of 1/1024 points.
+func (sb *SpanBuilder) Rise(units int) *SpanBuilder {
+	return sb.Attr("rise", strconv.Itoa(units))
+}
+
+// Text returns the span containing the escaped text s.
+func (sb *SpanBuilder) Text(s string) string {
+	return sb.Markup(PangoEscape(s))
+}
+
+// Markup returns the span containing markup as is,
+// e.g. to nest spans built by other SpanBuilders.
+// If no attribute is set, markup is returned without span.
+func (sb *SpanBuilder) Markup(markup ...string) string {
+	content := strings.Join(markup, "")
+	if len(sb.attrs) == 0 {
+		return content
+	}
+	return "<span " + strings.Join(sb.attrs, " ") + ">" + content + "</span>"
+}
@@ -0,0 +1,33 @@
+package i3bar
+
+import "testing"
+
+func TestSpanBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"no attributes", Span().Text("a < b"), "a &lt; b"},
+		{"colors", Span().Foreground("#ff0000").Background("#000000").Text("a"), `<span foreground="#ff0000" background="#000000">a</span>`},
+		{"font", Span().Font("Monospace 10").Size("small").Text("a"), `<span font="Monospace 10" size="small">a</span>`},
+		{"style", Span().Bold().Italic().Underline().Strikethrough().Text("a"),
+			`<span weight="bold" style="italic" underline="single" strikethrough="true">a</span>`},
+		{"rise", Span().Rise(-2048).Text("a"), `<span rise="-2048">a</span>`},
+		{"escaped attribute", Span().Attr("font", `"Sans" & <Mono>`).Text("a"), `<span font="&quot;Sans&quot; &amp; &lt;Mono&gt;">a</span>`},
+		{"escaped text", Span().Bold().Text("<b>&"), `<span weight="bold">&lt;b&gt;&amp;</span>`},
+		{"nested markup", Span().Foreground("#ff0000").Markup(Span().Bold().Text("a"), "&amp;"),
+			`<span foreground="#ff0000"><span weight="bold">a</span>&amp;</span>`},
+		{"markup without attributes", Span().Markup("<b>a</b>"), "<b>a</b>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+			if err := ValidatePango(tt.got); err != nil {
+				t.Errorf("invalid markup: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package i3bar
+
+import (
+	"math"
+	"strings"
+)
+
+// brailleBase is the empty braille pattern.
+const brailleBase = 0x2800
+
+// brailleDots are the dots of the left and the right column
+// of a braille character from the bottom to the top.
+var brailleDots = [2][4]rune{
+	{0x40, 0x04, 0x02, 0x01},
+	{0x80, 0x20, 0x10, 0x08},
+}
+
+// Sparkline renders samples as a braille sparkline with two samples per
+// character, scaled from 0 to the largest sample. Each sample is drawn with
+// one of five levels: no dot for zero and one to four dots for all other
+// samples, so that small but nonzero samples remain visible.
+// This suits non-negative samples like CPU load or network throughput.
+func Sparkline(samples []float64) string {
+	max := 0.0
+	for _, v := range samples {
+		max = math.Max(max, v)
+	}
+	return SparklineRange(samples, 0, max)
+}
+
+// SparklineRange renders samples as a braille sparkline like Sparkline,
+// but scaled from min to max. Samples outside are clamped, but nonzero
+// samples at or below min still get one dot.
+func SparklineRange(samples []float64, min, max float64) string {
+	var sb strings.Builder
+	for i := 0; i < len(samples); i += 2 {
+		char := rune(brailleBase)
+		for col := 0; col < 2 && i+col < len(samples); col++ {
+			level := sparkLevel(samples[i+col], min, max)
+			for row := 0; row < level; row++ {
+				char |= brailleDots[col][row]
+			}
+		}
+		sb.WriteRune(char)
+	}
+	return sb.String()
+}
+
+// sparkLevel scales v from min and max to the dot levels 1 to 4.
+// Zero and NaN samples have level 0.
+func sparkLevel(v, min, max float64) int {
+	if v == 0 || math.IsNaN(v) {
+		return 0
+	}
+	if max <= min {
+		return 1
+	}
+	level := int(math.Round((v - min) / (max - min) * 4))
+	if level < 1 {
+		return 1
+	}
+	if level > 4 {
+		return 4
+	}
+	return level
+}
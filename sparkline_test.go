@@ -0,0 +1,61 @@
+package i3bar
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// sparkDots returns the number of dots per sample of sparkline,
+// which has to contain n samples.
+func sparkDots(t *testing.T, sparkline string, n int) []int {
+	t.Helper()
+	chars := []rune(sparkline)
+	if want := (n + 1) / 2; len(chars) != want {
+		t.Fatalf("got %d characters, want %d", len(chars), want)
+	}
+	dots := make([]int, n)
+	for i := range dots {
+		char := chars[i/2] - brailleBase
+		for _, dot := range brailleDots[i%2] {
+			if char&dot != 0 {
+				dots[i]++
+			}
+		}
+	}
+	return dots
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []float64
+		min     float64
+		max     float64
+		want    []int
+	}{
+		{"levels", []float64{0, 1, 2, 3, 4}, 0, 4, []int{0, 1, 2, 3, 4}},
+		{"clamped", []float64{-5, 10}, 0, 4, []int{1, 4}},
+		// nonzero samples are visible, even if they round to level 0
+		{"small sample", []float64{0.1, 100}, 0, 100, []int{1, 4}},
+		{"window minimum", []float64{20, 50, 80}, 20, 80, []int{1, 2, 4}},
+		{"zero and nan", []float64{0, math.NaN()}, 0, 4, []int{0, 0}},
+		{"empty range", []float64{0, 5}, 5, 5, []int{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sparkDots(t, SparklineRange(tt.samples, tt.min, tt.max), len(tt.samples))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SparklineRange(%v, %v, %v) has dots %v, want %v", tt.samples, tt.min, tt.max, got, tt.want)
+			}
+		})
+	}
+
+	// Sparkline scales to the largest sample
+	if got, want := sparkDots(t, Sparkline([]float64{0, 0.5, 2}), 3), []int{0, 1, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sparkline has dots %v, want %v", got, want)
+	}
+	if got := Sparkline(nil); got != "" {
+		t.Errorf("Sparkline(nil) = %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package i3bar
+
+import "sync"
+
+// SpinnerStyle is the sequence of frames shown by a Spinner.
+type SpinnerStyle []string
+
+var (
+	// SpinnerBraille is a dot circling within a braille character.
+	SpinnerBraille = SpinnerStyle{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	// SpinnerDots is a growing row of dots.
+	SpinnerDots = SpinnerStyle{"   ", ".  ", ".. ", "..."}
+	// SpinnerLine is a rotating line.
+	SpinnerLine = SpinnerStyle{"-", "\\", "|", "/"}
+)
+
+// Spinner yields the frames of a SpinnerStyle one after another,
+// e.g. to show that a module is still fetching its data.
+type Spinner struct {
+	mux   sync.Mutex
+	style SpinnerStyle
+	frame int
+}
+
+// NewSpinner creates a Spinner showing the frames of style.
+// SpinnerBraille is used if style is empty.
+func NewSpinner(style SpinnerStyle) *Spinner {
+	if len(style) == 0 {
+		style = SpinnerBraille
+	}
+	return &Spinner{style: style}
+}
+
+// Frame returns the current frame.
+func (s *Spinner) Frame() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.style[s.frame]
+}
+
+// Next returns the current frame and advances to the next one,
+// so it should be called once per tick.
+func (s *Spinner) Next() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	frame := s.style[s.frame]
+	s.frame = (s.frame + 1) % len(s.style)
+	return frame
+}
+
+// Reset restarts the spinner at its first frame.
+func (s *Spinner) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.frame = 0
+}
@@ -0,0 +1,47 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpinner(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  SpinnerStyle
+		frames []string
+	}{
+		{"line", SpinnerLine, []string{"-", "\\", "|", "/", "-"}},
+		{"dots", SpinnerDots, []string{"   ", ".  ", ".. ", "...", "   "}},
+		{"default", nil, []string{"⠋", "⠙", "⠹"}},
+		{"single frame", SpinnerStyle{"*"}, []string{"*", "*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSpinner(tt.style)
+			var frames []string
+			for range tt.frames {
+				if current := s.Frame(); current != s.Frame() {
+					t.Error("Frame advanced the spinner")
+				}
+				frames = append(frames, s.Next())
+			}
+			if !reflect.DeepEqual(frames, tt.frames) {
+				t.Errorf("got frames %q, want %q", frames, tt.frames)
+			}
+		})
+	}
+}
+
+func TestSpinnerReset(t *testing.T) {
+	s := NewSpinner(SpinnerLine)
+	s.Next()
+	s.Next()
+	if got := s.Frame(); got != "|" {
+		t.Errorf("Frame() = %q, want |", got)
+	}
+	s.Reset()
+	if got := s.Frame(); got != "-" {
+		t.Errorf("Frame() = %q after Reset, want -", got)
+	}
+}
@@ -0,0 +1,93 @@
+package i3bar
+
+import (
+	"html"
+	"sync"
+)
+
+// StableWidth keeps the positions of blocks steady across updates by
+// remembering the widest text rendered for every block and setting it
+// as MinWidthText, so that i3bar pads narrower texts to its width
+// according to the Align of the block.
+//
+// Blocks are identified by their name and instance, unnamed blocks are
+// left unchanged. So are blocks with an explicit MinWidth and blocks with
+// a LazyText, which is not evaluated before the status line is encoded.
+//
+// The zero value is ready to use.
+type StableWidth struct {
+	// Metrics measures texts in pixels if set.
+	// Otherwise the widest text is the one with the most graphemes.
+	Metrics *FontMetrics
+
+	mux    sync.Mutex
+	widest map[clickRoute]stableText
+}
+
+// stableText is the widest text seen for a block.
+type stableText struct {
+	text  string
+	width int
+}
+
+// NewStableWidth creates a StableWidth without any widths remembered.
+func NewStableWidth() *StableWidth {
+	return &StableWidth{widest: map[clickRoute]stableText{}}
+}
+
+// Apply sets the MinWidth of b to the widest text seen for it so far.
+func (sw *StableWidth) Apply(b *Block) {
+	if b == nil || b.Name == "" || b.MinWidth != nil || (b.FullText == "" && b.LazyText != nil) {
+		return
+	}
+	plain := b.FullText
+	if b.Markup == Pango {
+		plain = html.UnescapeString(anyTag.ReplaceAllString(plain, ""))
+	}
+	width := 0
+	if sw.Metrics != nil {
+		width = sw.Metrics.Width(plain)
+	} else {
+		width = GraphemeCount(plain)
+	}
+
+	sw.mux.Lock()
+	defer sw.mux.Unlock()
+	if sw.widest == nil {
+		sw.widest = map[clickRoute]stableText{}
+	}
+	key := clickRoute{name: b.Name, instance: b.Instance}
+	widest, ok := sw.widest[key]
+	if !ok || width > widest.width {
+		widest = stableText{text: b.FullText, width: width}
+		sw.widest[key] = widest
+	}
+	b.MinWidth = MinWidthText(widest.text)
+}
+
+// Middleware returns a Middleware applying StableWidth to all blocks.
+// Use it after all middlewares changing the texts of blocks.
+func (sw *StableWidth) Middleware() Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			sw.Apply(b)
+		}
+		return line
+	}
+}
+
+// Reset forgets the widths of all blocks,
+// e.g. after the layout of the status line changed.
+func (sw *StableWidth) Reset() {
+	sw.mux.Lock()
+	defer sw.mux.Unlock()
+	sw.widest = map[clickRoute]stableText{}
+}
+
+// ResetBlock forgets the width of the block with name and instance,
+// so that it may shrink again.
+func (sw *StableWidth) ResetBlock(name, instance string) {
+	sw.mux.Lock()
+	defer sw.mux.Unlock()
+	delete(sw.widest, clickRoute{name: name, instance: instance})
+}
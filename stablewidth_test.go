@@ -0,0 +1,40 @@
+package i3bar
+
+import "testing"
+
+// monospace are the metrics of a font with glyphs 5 pixels wide.
+var monospace = &FontMetrics{
+	Size:       10,
+	unitsPerEm: 1000,
+	advances:   []uint16{500},
+	glyph:      func(rune) uint16 { return 0 },
+}
+
+func TestStableWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		sw   *StableWidth
+	}{
+		{"constructor", NewStableWidth()},
+		{"zero value", &StableWidth{}},
+		{"zero value with metrics", &StableWidth{Metrics: monospace}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, text := range []string{"100%", "5%"} {
+				b := &Block{Name: "cpu", FullText: text}
+				tt.sw.Apply(b)
+				if b.MinWidth == nil || b.MinWidth.Text != "100%" {
+					t.Fatalf("got MinWidth %+v for %q, want 100%%", b.MinWidth, text)
+				}
+			}
+
+			b := &Block{Name: "cpu", FullText: "5%"}
+			tt.sw.ResetBlock("cpu", "")
+			tt.sw.Apply(b)
+			if b.MinWidth == nil || b.MinWidth.Text != "5%" {
+				t.Errorf("got MinWidth %+v after ResetBlock, want 5%%", b.MinWidth)
+			}
+		})
+	}
+}
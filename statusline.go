@@ -0,0 +1,81 @@
+package i3bar
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Index returns the index of the first block identified by name and instance
+// or -1 if there is no such block.
+func (l StatusLine) Index(name, instance string) int {
+	for i, b := range l {
+		if b != nil && b.Name == name && b.Instance == instance {
+			return i
+		}
+	}
+	return -1
+}
+
+// Find returns the first block identified by name and instance
+// or nil if there is no such block.
+func (l StatusLine) Find(name, instance string) *Block {
+	if i := l.Index(name, instance); i >= 0 {
+		return l[i]
+	}
+	return nil
+}
+
+// Insert inserts blocks at index i. If i is out of range,
+// the blocks are appended to the StatusLine.
+func (l *StatusLine) Insert(i int, blocks ...*Block) {
+	if i < 0 || i > len(*l) {
+		i = len(*l)
+	}
+	line := make(StatusLine, 0, len(*l)+len(blocks))
+	line = append(line, (*l)[:i]...)
+	line = append(line, blocks...)
+	line = append(line, (*l)[i:]...)
+	*l = line
+}
+
+// Remove removes the first block identified by name and instance.
+// It returns false if there is no such block.
+func (l *StatusLine) Remove(name, instance string) bool {
+	i := l.Index(name, instance)
+	if i < 0 {
+		return false
+	}
+	line := *l
+	copy(line[i:], line[i+1:])
+	line[len(line)-1] = nil
+	*l = line[:len(line)-1]
+	return true
+}
+
+// Replace replaces the first block identified by name and instance with b.
+// It returns false if there is no such block.
+func (l StatusLine) Replace(name, instance string, b *Block) bool {
+	i := l.Index(name, instance)
+	if i < 0 {
+		return false
+	}
+	l[i] = b
+	return true
+}
+
+// UnmarshalJSON decodes a status line as sent by another
+// i3bar protocol producer. Blocks must not be null.
+func (l *StatusLine) UnmarshalJSON(data []byte) error {
+	var blocks []*Block
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return errors.Wrap(err, "Failed to decode status line")
+	}
+	for i, b := range blocks {
+		if b == nil {
+			return errors.Errorf("block %d is null", i)
+		}
+	}
+	*l = blocks
+	return nil
+}
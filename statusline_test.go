@@ -0,0 +1,104 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+// names returns name/instance of all blocks of line.
+func names(line StatusLine) []string {
+	var ids []string
+	for _, b := range line {
+		ids = append(ids, b.Name+"/"+b.Instance)
+	}
+	return ids
+}
+
+func newLine() StatusLine {
+	return StatusLine{{Name: "a"}, {Name: "b", Instance: "0"}, {Name: "b", Instance: "1"}, {Name: "b", Instance: "0", FullText: "second"}}
+}
+
+func TestStatusLineFind(t *testing.T) {
+	line := newLine()
+	tests := []struct {
+		name, instance string
+		index          int
+	}{
+		{"a", "", 0},
+		{"b", "1", 2},
+		// the first of several blocks is found
+		{"b", "0", 1},
+		// the instance has to match
+		{"b", "", -1},
+		{"c", "", -1},
+	}
+	for _, tt := range tests {
+		if got := line.Index(tt.name, tt.instance); got != tt.index {
+			t.Errorf("Index(%q, %q) = %d, want %d", tt.name, tt.instance, got, tt.index)
+		}
+		got := line.Find(tt.name, tt.instance)
+		if (tt.index < 0 && got != nil) || (tt.index >= 0 && got != line[tt.index]) {
+			t.Errorf("Find(%q, %q) = %+v", tt.name, tt.instance, got)
+		}
+	}
+	if got := (StatusLine{nil, {Name: "a"}}).Index("a", ""); got != 1 {
+		t.Errorf("Index with nil block = %d, want 1", got)
+	}
+}
+
+func TestStatusLineInsert(t *testing.T) {
+	tests := []struct {
+		index int
+		want  []string
+	}{
+		{0, []string{"x/", "y/", "a/", "b/0", "b/1", "b/0"}},
+		{2, []string{"a/", "b/0", "x/", "y/", "b/1", "b/0"}},
+		{4, []string{"a/", "b/0", "b/1", "b/0", "x/", "y/"}},
+		// out of range indexes append
+		{5, []string{"a/", "b/0", "b/1", "b/0", "x/", "y/"}},
+		{-1, []string{"a/", "b/0", "b/1", "b/0", "x/", "y/"}},
+	}
+	for _, tt := range tests {
+		line := newLine()
+		orig := line[:2:2]
+		line.Insert(tt.index, &Block{Name: "x"}, &Block{Name: "y"})
+		if got := names(line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Insert(%d) = %v, want %v", tt.index, got, tt.want)
+		}
+		if got := names(orig); !reflect.DeepEqual(got, []string{"a/", "b/0"}) {
+			t.Errorf("Insert(%d) modified the original array: %v", tt.index, got)
+		}
+	}
+}
+
+func TestStatusLineRemove(t *testing.T) {
+	line := newLine()
+	if !line.Remove("b", "0") {
+		t.Fatal("Remove(b, 0) = false")
+	}
+	if got, want := names(line), []string{"a/", "b/1", "b/0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if line.Remove("c", "") {
+		t.Error("Remove(c) = true")
+	}
+	if !line.Remove("b", "0") || !line.Remove("a", "") || !line.Remove("b", "1") {
+		t.Fatal("removing remaining blocks failed")
+	}
+	if len(line) != 0 {
+		t.Errorf("got %v, want an empty status line", names(line))
+	}
+}
+
+func TestStatusLineReplace(t *testing.T) {
+	line := newLine()
+	if !line.Replace("b", "1", &Block{Name: "c"}) {
+		t.Fatal("Replace(b, 1) = false")
+	}
+	if got, want := names(line), []string{"a/", "b/0", "c/", "b/0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if line.Replace("b", "1", &Block{Name: "d"}) {
+		t.Error("replacing a missing block succeeded")
+	}
+}
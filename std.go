@@ -0,0 +1,161 @@
+package i3bar
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// NewStdStream initializes a new i3bar protocol stream on os.Stdout
+// and reads click events from os.Stdin if h.ClickEvents is set or
+// enabled by WithClickEvents or WithClickHandler.
+//
+// The header is validated before it is sent.
+// opts can be used to further configure the stream.
+func NewStdStream(h Header, opts ...Option) (*Stream, error) {
+	probe := probeOptions(h, opts)
+	if err := probe.header.validate(probe.allowUnknownVersion); err != nil {
+		return nil, errors.Wrap(err, "Invalid header")
+	}
+
+	var r io.Reader
+	if probe.header.ClickEvents {
+		r = os.Stdin
+	}
+
+	return NewStream(os.Stdout, r, false, h, opts...)
+}
+
+// OnPause registers fn to be called once the stream got paused, either by
+// Pause or the custom stop signal declared in the header, e.g. because the
+// bar got hidden. fn is not called for the default SIGSTOP, which can not
+// be caught. fn may be used to suspend timers of expensive blocks.
+//
+// This function is thread safe.
+func (s *Stream) OnPause(fn func()) {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.onPause = fn
+}
+
+// OnResume registers fn to be called once the stream got resumed, either by
+// Resume, the continue signal declared in the header or by Reconnect. The default
+// SIGCONT only resumes the stream if the header declares a custom stop signal.
+//
+// This function is thread safe.
+func (s *Stream) OnResume(fn func()) {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.onResume = fn
+}
+
+// probeOptions applies opts to a scratch Stream with header h,
+// so that their effect can be inspected before the stream is set up,
+// e.g. whether a click handler enabled click events.
+func probeOptions(h Header, opts []Option) *Stream {
+	probe := &Stream{header: h}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	return probe
+}
+
+// handleSignals installs handlers for custom stop and continue signals,
+// so that the stream pauses sending status lines between them.
+// Default signals (SIGSTOP and SIGCONT) are handled by the kernel.
+// If only the stop signal is custom, the process is never stopped by
+// the kernel, so the default SIGCONT resumes the stream.
+func (s *Stream) handleSignals(stop, cont syscall.Signal) {
+	var sigs []os.Signal
+	if stop != 0 && stop != syscall.SIGSTOP {
+		sigs = append(sigs, stop)
+		if cont == 0 || cont == syscall.SIGCONT {
+			cont = syscall.SIGCONT
+			sigs = append(sigs, cont)
+		}
+	}
+	if cont != 0 && cont != syscall.SIGCONT {
+		sigs = append(sigs, cont)
+	}
+	if len(sigs) == 0 {
+		return
+	}
+
+	s.sigs = make(chan os.Signal, 1)
+	signal.Notify(s.sigs, sigs...)
+
+	go func(c <-chan os.Signal) {
+		for sig := range c {
+			switch sig {
+			case stop:
+				s.Pause()
+			case cont:
+				if err := s.Resume(); err != nil {
+					s.reportError(err)
+				}
+			}
+		}
+	}(s.sigs)
+}
+
+// OnReload registers fn to be called whenever one of sigs is received,
+// e.g. to re-read the configuration without restarting the bar.
+// If no signals are given, SIGHUP is used.
+// fn is called from a single goroutine, so calls never overlap.
+// A previously registered reload handler is replaced, a nil fn
+// removes it. The handler is removed once the stream is closed.
+//
+// This function is thread safe.
+func (s *Stream) OnReload(fn func(), sigs ...os.Signal) {
+	s.wMux.Lock()
+	defer s.wMux.Unlock()
+	s.stopReload()
+	if fn == nil {
+		return
+	}
+	s.dMux.Lock()
+	shut := s.shut
+	s.dMux.Unlock()
+	if shut {
+		return
+	}
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+
+	s.reload = make(chan os.Signal, 1)
+	signal.Notify(s.reload, sigs...)
+
+	go func(c <-chan os.Signal) {
+		for range c {
+			fn()
+		}
+	}(s.reload)
+}
+
+// stopSignals removes the installed signal handlers.
+// The caller has to hold wMux.
+func (s *Stream) stopSignals() {
+	s.stopReload()
+	s.stopRefresh()
+	if s.sigs == nil {
+		return
+	}
+	signal.Stop(s.sigs)
+	close(s.sigs)
+	s.sigs = nil
+}
+
+// stopReload removes the installed reload handler.
+// The caller has to hold wMux.
+func (s *Stream) stopReload() {
+	if s.reload == nil {
+		return
+	}
+	signal.Stop(s.reload)
+	close(s.reload)
+	s.reload = nil
+}
@@ -0,0 +1,147 @@
+package i3bar
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it is true or a second has passed.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestHandleSignals(t *testing.T) {
+	tests := []struct {
+		name       string
+		stop, cont syscall.Signal
+		sendCont   syscall.Signal
+	}{
+		{"custom stop and cont", syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGUSR2},
+		{"custom stop with default cont", syscall.SIGUSR1, 0, syscall.SIGCONT},
+		{"custom stop with explicit SIGCONT", syscall.SIGUSR1, syscall.SIGCONT, syscall.SIGCONT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHeader(tt.stop, tt.cont, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			s, err := NewStream(&out, nil, false, h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			if err := syscall.Kill(syscall.Getpid(), tt.stop); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "pause", s.Paused)
+
+			if err := syscall.Kill(syscall.Getpid(), tt.sendCont); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "resume", func() bool { return !s.Paused() })
+		})
+	}
+}
+
+func TestOnReload(t *testing.T) {
+	tests := []struct {
+		name string
+		sigs []os.Signal
+		send syscall.Signal
+	}{
+		{"default SIGHUP", nil, syscall.SIGHUP},
+		{"custom signal", []os.Signal{syscall.SIGUSR1}, syscall.SIGUSR1},
+		{"several signals", []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2}, syscall.SIGUSR2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+
+			var reloads int32
+			s.OnReload(func() { atomic.AddInt32(&reloads, 1) }, tt.sigs...)
+			for i := int32(1); i <= 2; i++ {
+				if err := syscall.Kill(syscall.Getpid(), tt.send); err != nil {
+					t.Fatal(err)
+				}
+				waitFor(t, "reload", func() bool { return atomic.LoadInt32(&reloads) == i })
+			}
+		})
+	}
+}
+
+func TestOnReloadRemove(t *testing.T) {
+	// keep SIGHUP caught, so that it never terminates the test
+	// once the reload handler is removed
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+	hup := func() {
+		t.Helper()
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-guard:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for SIGHUP")
+		}
+		// give removed handlers the chance to run
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first, second int32
+	s.OnReload(func() { atomic.AddInt32(&first, 1) })
+	// a new handler replaces the previous one
+	s.OnReload(func() { atomic.AddInt32(&second, 1) })
+	hup()
+	if atomic.LoadInt32(&first) != 0 || atomic.LoadInt32(&second) != 1 {
+		t.Errorf("got %d calls of the replaced and %d of the new handler, want 0 and 1", atomic.LoadInt32(&first), atomic.LoadInt32(&second))
+	}
+
+	// a nil handler removes the handler
+	s.OnReload(nil)
+	hup()
+	if atomic.LoadInt32(&second) != 1 {
+		t.Error("removed handler got called")
+	}
+
+	// closing the stream removes the handler
+	s.OnReload(func() { atomic.AddInt32(&second, 1) })
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	hup()
+	if atomic.LoadInt32(&second) != 1 {
+		t.Error("handler called after Close")
+	}
+
+	// no handler is registered on closed streams
+	s.OnReload(func() { atomic.AddInt32(&second, 1) })
+	hup()
+	if atomic.LoadInt32(&second) != 1 {
+		t.Error("handler of a closed stream got called")
+	}
+}
@@ -0,0 +1,201 @@
+package i3bar
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Template renders the FullText and ShortText of a Block from text/template
+// templates. The functions of TemplateFuncs are available in both templates.
+type Template struct {
+	full  *template.Template
+	short *template.Template
+}
+
+// NewTemplate parses the templates for FullText and ShortText.
+// shortText may be empty if no ShortText should be rendered.
+// funcs are available in both templates in addition to TemplateFuncs.
+func NewTemplate(fullText, shortText string, funcs ...template.FuncMap) (*Template, error) {
+	t := &Template{}
+	var err error
+	if t.full, err = parseTemplate("full_text", fullText, funcs); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse full_text template")
+	}
+	if shortText != "" {
+		if t.short, err = parseTemplate("short_text", shortText, funcs); err != nil {
+			return nil, errors.Wrap(err, "Failed to parse short_text template")
+		}
+	}
+	return t, nil
+}
+
+// parseTemplate parses text as the template name with TemplateFuncs and funcs.
+func parseTemplate(name, text string, funcs []template.FuncMap) (*template.Template, error) {
+	t := template.New(name).Funcs(TemplateFuncs())
+	for _, f := range funcs {
+		t.Funcs(f)
+	}
+	return t.Parse(text)
+}
+
+// MustTemplate is like NewTemplate but panics if a template is invalid.
+func MustTemplate(fullText, shortText string, funcs ...template.FuncMap) *Template {
+	t, err := NewTemplate(fullText, shortText, funcs...)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Funcs replaces functions of both templates. Functions which are not
+// used by the templates yet have to be passed to NewTemplate instead,
+// as the templates are parsed there.
+// This must be called before the first Render.
+func (t *Template) Funcs(funcs template.FuncMap) *Template {
+	t.full.Funcs(funcs)
+	if t.short != nil {
+		t.short.Funcs(funcs)
+	}
+	return t
+}
+
+// Icons selects the icon set of the icon function.
+// This must be called before the first Render.
+func (t *Template) Icons(set IconSet) *Template {
+	return t.Funcs(template.FuncMap{"icon": set.icon})
+}
+
+// Render executes the templates with data and sets the FullText
+// and ShortText of b.
+func (t *Template) Render(b *Block, data interface{}) error {
+	full, err := execute(t.full, data)
+	if err != nil {
+		return err
+	}
+	short := ""
+	if t.short != nil {
+		if short, err = execute(t.short, data); err != nil {
+			return err
+		}
+	}
+	b.FullText = full
+	b.ShortText = short
+	return nil
+}
+
+// execute executes t with data and returns the result.
+func execute(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", errors.Wrapf(err, "Failed to render %s template", t.Name())
+	}
+	return buf.String(), nil
+}
+
+// TemplateFuncs returns the functions available in a Template:
+//
+//	lpad n s       pads s with spaces on the left to a width of n runes
+//	rpad n s       pads s with spaces on the right to a width of n runes
+//	bytes n        humanizes a byte count, e.g. 1.5 KiB
+//	bar n f        renders the fraction f as a bar of n characters
+//	color name     returns a color in hex notation, e.g. color "steelblue"
+//	fg color m     wraps the markup m in a pango span with the foreground color
+//	bg color m     wraps the markup m in a pango span with the background color
+//	escape s       escapes s for use within pango markup
+//	icon name      returns the glyph of an icon from FontAwesome or the
+//	               icon set selected by Template.Icons, e.g. icon "battery-75"
+//
+// fg and bg require the Block to use Pango markup. They do not escape
+// their argument, so that spans can be nested; escape plain texts first,
+// e.g. {{fg "red" (escape .Name)}}.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"lpad": func(n int, s string) string {
+			return pad(s, n, true)
+		},
+		"rpad": func(n int, s string) string {
+			return pad(s, n, false)
+		},
+		"bytes": func(n interface{}) (string, error) {
+			v, err := toFloat(n)
+			if err != nil {
+				return "", err
+			}
+			return HumanBytes(v, 1, Binary), nil
+		},
+		"bar": func(n int, f interface{}) (string, error) {
+			v, err := toFloat(f)
+			if err != nil {
+				return "", err
+			}
+			return Bar(v, n), nil
+		},
+		"color": func(name string) (string, error) {
+			c, err := ParseColor(name)
+			return string(c), err
+		},
+		"fg": func(c, s string) (string, error) {
+			color, err := ParseColor(c)
+			if err != nil {
+				return "", err
+			}
+			return Span().Foreground(color).Markup(s), nil
+		},
+		"bg": func(c, s string) (string, error) {
+			color, err := ParseColor(c)
+			if err != nil {
+				return "", err
+			}
+			return Span().Background(color).Markup(s), nil
+		},
+		"escape": PangoEscape,
+		"icon":   FontAwesome.icon,
+	}
+}
+
+// pad pads s with spaces to a width of n runes.
+func pad(s string, n int, left bool) string {
+	missing := n - utf8.RuneCountInString(s)
+	if missing <= 0 {
+		return s
+	}
+	if left {
+		return strings.Repeat(" ", missing) + s
+	}
+	return s + strings.Repeat(" ", missing)
+}
+
+// toFloat converts any number to float64.
+func toFloat(n interface{}) (float64, error) {
+	switch v := n.(type) {
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, errors.Errorf("not a number: %v", n)
+}
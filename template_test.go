@@ -0,0 +1,118 @@
+package i3bar
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		full string
+		data interface{}
+		want string
+	}{
+		{"field", `cpu {{.}}%`, 42, "cpu 42%"},
+		{"lpad", `[{{lpad 4 .}}]`, "ab", "[  ab]"},
+		{"rpad", `[{{rpad 4 .}}]`, "ab", "[ab  ]"},
+		// wider texts are not truncated
+		{"pad too wide", `[{{lpad 1 .}}]`, "abc", "[abc]"},
+		{"pad runes", `[{{lpad 3 .}}]`, "äö", "[ äö]"},
+		{"bytes int", `{{bytes .}}`, 1536, "1.5 KiB"},
+		{"bytes uint64", `{{bytes .}}`, uint64(1024 * 1024), "1.0 MiB"},
+		{"bar", `{{bar 4 .}}`, 0.5, "██  "},
+		{"color", `{{color "red"}}`, nil, "#ff0000"},
+		{"escape", `{{escape .}}`, "a & b", "a &amp; b"},
+		{"icon", `{{icon "battery"}}`, nil, FontAwesome["battery"]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := NewTemplate(tt.full, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := &Block{ShortText: "stale"}
+			if err := tmpl.Render(b, tt.data); err != nil {
+				t.Fatal(err)
+			}
+			if b.FullText != tt.want {
+				t.Errorf("got %q, want %q", b.FullText, tt.want)
+			}
+			// without a short_text template the ShortText is cleared
+			if b.ShortText != "" {
+				t.Errorf("ShortText = %q, want empty", b.ShortText)
+			}
+		})
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		full, short string
+		data        interface{}
+		parse       bool
+	}{
+		{"invalid full_text", `{{`, "", nil, true},
+		{"invalid short_text", `ok`, `{{end}}`, nil, true},
+		{"unknown function", `{{nope}}`, "", nil, true},
+		{"not a number", `{{bytes .}}`, "", "many", false},
+		{"unknown color", `{{fg "nocolor" "x"}}`, "", nil, false},
+		{"unknown icon", `{{icon "nope"}}`, "", nil, false},
+		{"short_text", `ok`, `{{bar 3 .}}`, "half", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := NewTemplate(tt.full, tt.short)
+			if tt.parse {
+				if err == nil {
+					t.Error("invalid template parsed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := &Block{FullText: "old"}
+			if err := tmpl.Render(b, tt.data); err == nil {
+				t.Error("Render succeeded")
+			}
+			// a failed render keeps the block untouched
+			if b.FullText != "old" {
+				t.Errorf("FullText = %q after failed render", b.FullText)
+			}
+		})
+	}
+}
+
+func TestTemplateShortText(t *testing.T) {
+	tmpl := MustTemplate(`{{.Name}} {{.Used}}`, `{{.Used}}`)
+	b := &Block{}
+	if err := tmpl.Render(b, struct{ Name, Used string }{"disk", "42%"}); err != nil {
+		t.Fatal(err)
+	}
+	if b.FullText != "disk 42%" || b.ShortText != "42%" {
+		t.Errorf("got %q and %q", b.FullText, b.ShortText)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	funcs := template.FuncMap{"upper": strings.ToUpper}
+	tmpl := MustTemplate(`{{upper .}} {{icon "wifi"}}`, `{{icon "wifi"}}`, funcs).
+		Icons(IconSet{"wifi": "W"})
+	b := &Block{}
+	if err := tmpl.Render(b, "up"); err != nil {
+		t.Fatal(err)
+	}
+	if b.FullText != "UP W" || b.ShortText != "W" {
+		t.Errorf("got %q and %q", b.FullText, b.ShortText)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustTemplate did not panic on an invalid template")
+		}
+	}()
+	MustTemplate(`{{`, "")
+}
@@ -0,0 +1,73 @@
+package i3bar
+
+// Theme is a palette applied to blocks which don't set explicit colors.
+type Theme struct {
+	// Foreground is the default text color.
+	Foreground Color
+
+	// Background is the default background color.
+	Background Color
+
+	// Border is the default border color.
+	Border Color
+
+	// UrgentForeground is the text color of urgent blocks.
+	UrgentForeground Color
+
+	// UrgentBackground is the background color of urgent blocks.
+	UrgentBackground Color
+
+	// Good is the color used by modules to signal a good state.
+	Good Color
+
+	// Degraded is the color used by modules to signal a degraded state.
+	Degraded Color
+
+	// Bad is the color used by modules to signal a bad state.
+	Bad Color
+
+	// Separator specifies if a separator line should be drawn after blocks.
+	// i3bar's default is used if this is nil.
+	Separator *bool
+
+	// SeparatorBlockWidth is the amount of pixels to leave blank after blocks.
+	// i3bar's default is used if this is 0.
+	SeparatorBlockWidth int
+}
+
+// Apply sets all unset colors and separator settings of b.
+func (t *Theme) Apply(b *Block) {
+	if b == nil {
+		return
+	}
+	if b.Urgent {
+		setColor(&b.Color, t.UrgentForeground)
+		setColor(&b.Background, t.UrgentBackground)
+	}
+	setColor(&b.Color, t.Foreground)
+	setColor(&b.Background, t.Background)
+	setColor(&b.Border, t.Border)
+	if b.Separator == nil && t.Separator != nil {
+		b.Separator = Bool(*t.Separator)
+	}
+	if b.SeparatorBlockWidth == 0 {
+		b.SeparatorBlockWidth = t.SeparatorBlockWidth
+	}
+}
+
+// Middleware returns a Middleware applying the Theme to all blocks.
+func (t *Theme) Middleware() Middleware {
+	return func(line StatusLine) StatusLine {
+		for _, b := range line {
+			t.Apply(b)
+		}
+		return line
+	}
+}
+
+// setColor sets dst to c if dst is unset.
+func setColor(dst *Color, c Color) {
+	if *dst == "" {
+		*dst = c
+	}
+}
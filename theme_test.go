@@ -0,0 +1,75 @@
+package i3bar
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testTheme = &Theme{
+	Foreground:          "#ffffff",
+	Background:          "#000000",
+	Border:              "#333333",
+	UrgentForeground:    "#000000",
+	UrgentBackground:    "#ff0000",
+	Separator:           Bool(false),
+	SeparatorBlockWidth: 12,
+}
+
+func TestThemeApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		block Block
+		want  Block
+	}{
+		{
+			name:  "unset",
+			block: Block{FullText: "a"},
+			want: Block{FullText: "a", Color: "#ffffff", Background: "#000000", Border: "#333333",
+				Separator: Bool(false), SeparatorBlockWidth: 12},
+		},
+		{
+			name: "explicit",
+			block: Block{FullText: "a", Color: "#00ff00", Background: "#0000ff", Border: "#00ffff",
+				Separator: Bool(true), SeparatorBlockWidth: 3},
+			want: Block{FullText: "a", Color: "#00ff00", Background: "#0000ff", Border: "#00ffff",
+				Separator: Bool(true), SeparatorBlockWidth: 3},
+		},
+		{
+			name:  "urgent",
+			block: Block{FullText: "a", Urgent: true},
+			want: Block{FullText: "a", Urgent: true, Color: "#000000", Background: "#ff0000", Border: "#333333",
+				Separator: Bool(false), SeparatorBlockWidth: 12},
+		},
+		{
+			name:  "urgent explicit color",
+			block: Block{FullText: "a", Urgent: true, Color: "#00ff00"},
+			want: Block{FullText: "a", Urgent: true, Color: "#00ff00", Background: "#ff0000", Border: "#333333",
+				Separator: Bool(false), SeparatorBlockWidth: 12},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.block
+			testTheme.Apply(&b)
+			if !reflect.DeepEqual(b, tt.want) {
+				t.Errorf("got %+v, want %+v", b, tt.want)
+			}
+		})
+	}
+
+	// applying to nil blocks is a no-op
+	testTheme.Apply(nil)
+}
+
+func TestThemeMiddleware(t *testing.T) {
+	line := testTheme.Middleware()(StatusLine{{FullText: "a"}, {FullText: "b", Color: "#00ff00"}})
+	if line[0].Color != "#ffffff" || line[1].Color != "#00ff00" {
+		t.Errorf("got colors %q and %q, want %q and %q", line[0].Color, line[1].Color, "#ffffff", "#00ff00")
+	}
+
+	// the separator setting is copied, not shared between blocks
+	*line[0].Separator = true
+	if *line[1].Separator || *testTheme.Separator {
+		t.Error("blocks share the separator setting of the theme")
+	}
+}
@@ -0,0 +1,73 @@
+package i3bar
+
+// Threshold is the style of blocks whose value is at least Min.
+type Threshold struct {
+	// Min is the smallest value the threshold applies to.
+	Min float64
+
+	// Color of the text, if set.
+	Color Color
+
+	// Background color, if set.
+	Background Color
+
+	// Urgent marks the block as urgent.
+	Urgent bool
+
+	// Icon is prepended to the text, if set.
+	Icon string
+}
+
+// Thresholds maps value ranges to styles, e.g. to color a cpu block
+// green, yellow and red. Each Threshold applies from its Min up to
+// the Min of the next greater Threshold.
+type Thresholds []Threshold
+
+// Match returns the Threshold with the greatest Min not above value.
+// ok is false if value is below all thresholds.
+func (t Thresholds) Match(value float64) (th Threshold, ok bool) {
+	for _, candidate := range t {
+		if candidate.Min <= value && (!ok || candidate.Min >= th.Min) {
+			th, ok = candidate, true
+		}
+	}
+	return th, ok
+}
+
+// Apply styles b according to the Threshold matching value
+// and returns b for convenience.
+func (t Thresholds) Apply(b *Block, value float64) *Block {
+	th, ok := t.Match(value)
+	if !ok {
+		return b
+	}
+	if th.Color != "" {
+		b.Color = th.Color
+	}
+	if th.Background != "" {
+		b.Background = th.Background
+	}
+	if th.Urgent {
+		b.Urgent = true
+	}
+	if th.Icon != "" {
+		prependIcon(b, th.Icon)
+	}
+	return b
+}
+
+// prependIcon prepends icon to the texts of b.
+func prependIcon(b *Block, icon string) {
+	prefix := icon + " "
+	if b.FullText == "" && b.LazyText != nil {
+		lazy := b.LazyText
+		b.LazyText = TextFunc(func() string {
+			return prefix + lazy.String()
+		})
+	} else {
+		b.FullText = prefix + b.FullText
+	}
+	if b.ShortText != "" {
+		b.ShortText = prefix + b.ShortText
+	}
+}
@@ -0,0 +1,57 @@
+package i3bar
+
+import "testing"
+
+func TestThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		value      float64
+		color      Color
+		text       string
+		urgent     bool
+	}{
+		{"below all", cpuThresholds, -1, "", "42%", false},
+		{"first min", cpuThresholds, 0, "#00ff00", "low 42%", false},
+		{"within first", cpuThresholds, 49.9, "#00ff00", "low 42%", false},
+		{"second min", cpuThresholds, 50, "#ffff00", "mid 42%", false},
+		{"last", cpuThresholds, 95, "#ff0000", "high 42%", true},
+		// only the matching range applies, the urgent low range does not
+		// leak into greater values
+		{"urgent low range", batteryThresholds, 5, "#ff0000", "empty 42%", true},
+		{"above urgent range", batteryThresholds, 80, "#00ff00", "full 42%", false},
+		// the order of the thresholds does not matter
+		{"unordered", Thresholds{cpuThresholds[2], cpuThresholds[0], cpuThresholds[1]}, 60, "#ffff00", "mid 42%", false},
+		{"empty", nil, 60, "", "42%", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.thresholds.Apply(&Block{Name: "any", FullText: "42%"}, tt.value)
+			if b.Color != tt.color || b.FullText != tt.text || b.Urgent != tt.urgent {
+				t.Errorf("got %q %q urgent %v, want %q %q urgent %v",
+					b.Color, b.FullText, b.Urgent, tt.color, tt.text, tt.urgent)
+			}
+		})
+	}
+}
+
+func TestThresholdsLazyText(t *testing.T) {
+	b := cpuThresholds.Apply(&Block{LazyText: TextFunc(func() string { return "42%" }), ShortText: "42"}, 95)
+	if got := b.Text(); got != "high 42%" {
+		t.Errorf("Text() = %q, want %q", got, "high 42%")
+	}
+	if b.ShortText != "high 42" {
+		t.Errorf("ShortText = %q, want %q", b.ShortText, "high 42")
+	}
+}
+
+var cpuThresholds = Thresholds{
+	{Min: 0, Color: "#00ff00", Icon: "low"},
+	{Min: 50, Color: "#ffff00", Icon: "mid"},
+	{Min: 90, Color: "#ff0000", Icon: "high", Urgent: true},
+}
+
+var batteryThresholds = Thresholds{
+	{Min: 0, Color: "#ff0000", Icon: "empty", Urgent: true},
+	{Min: 20, Color: "#00ff00", Icon: "full"},
+}
@@ -0,0 +1,139 @@
+package i3bar
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Toggle is a two-state block flipped by a left click,
+// e.g. to toggle do not disturb, caffeine or a VPN.
+type Toggle struct {
+	// Name of the toggle block.
+	Name string
+
+	// Instance of the toggle block.
+	Instance string
+
+	// OnText and OffText are shown for the respective state.
+	OnText, OffText string
+
+	// OnColor and OffColor are the text colors of the respective state.
+	OnColor, OffColor Color
+
+	// OnError is called if the state could not be persisted.
+	OnError func(err error)
+
+	mux      sync.Mutex
+	on       bool
+	path     string
+	onChange func(on bool)
+}
+
+// NewToggle creates a Toggle in state on. onChange is called with
+// the new state whenever the state got flipped by clicking.
+func NewToggle(name string, on bool, onChange func(on bool)) *Toggle {
+	return &Toggle{
+		Name:     name,
+		OnText:   "on",
+		OffText:  "off",
+		on:       on,
+		onChange: onChange,
+	}
+}
+
+// Persist stores the state in the file at path from now on.
+// If the file exists, the state is restored from it.
+func (t *Toggle) Persist(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "Failed to read toggle state")
+	}
+
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.path = path
+	if err == nil {
+		switch state := strings.TrimSpace(string(data)); state {
+		case "on":
+			t.on = true
+		case "off":
+			t.on = false
+		default:
+			return errors.Errorf("unknown toggle state: %s", state)
+		}
+	}
+	return nil
+}
+
+// On reports whether the toggle is on.
+func (t *Toggle) On() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.on
+}
+
+// Set changes the state without calling onChange,
+// e.g. if it got changed by another program.
+func (t *Toggle) Set(on bool) error {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.on = on
+	return t.save()
+}
+
+// Handle flips the state on left clicks.
+// All other click events are ignored.
+func (t *Toggle) Handle(ev ClickEvent) {
+	if ev.Button != LeftClick {
+		return
+	}
+
+	t.mux.Lock()
+	t.on = !t.on
+	on := t.on
+	err := t.save()
+	t.mux.Unlock()
+
+	if err != nil && t.OnError != nil {
+		t.OnError(err)
+	}
+	if t.onChange != nil {
+		t.onChange(on)
+	}
+}
+
+// Block renders the current state. The block handles its own
+// click events, see Block.OnClick.
+func (t *Toggle) Block() *Block {
+	b := &Block{
+		Name:     t.Name,
+		Instance: t.Instance,
+		FullText: t.OffText,
+		Color:    t.OffColor,
+		OnClick:  t.Handle,
+	}
+	if t.On() {
+		b.FullText = t.OnText
+		b.Color = t.OnColor
+	}
+	return b
+}
+
+// save persists the state if a path is set.
+// The caller has to hold mux.
+func (t *Toggle) save() error {
+	if t.path == "" {
+		return nil
+	}
+	state := "off"
+	if t.on {
+		state = "on"
+	}
+	if err := os.WriteFile(t.path, []byte(state+"\n"), 0644); err != nil {
+		return errors.Wrap(err, "Failed to persist toggle state")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package i3bar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestToggle(t *testing.T) {
+	tests := []struct {
+		name    string
+		on      bool
+		events  []ClickEvent
+		want    bool
+		changes []bool
+	}{
+		{"initial", true, nil, true, nil},
+		{"left click", false, []ClickEvent{{Button: LeftClick}}, true, []bool{true}},
+		{"twice", true, []ClickEvent{{Button: LeftClick}, {Button: LeftClick}}, true, []bool{false, true}},
+		{"other buttons", false, []ClickEvent{{Button: RightClick}, {Button: ScrollUp}}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes []bool
+			toggle := NewToggle("dnd", tt.on, func(on bool) { changes = append(changes, on) })
+			toggle.OnColor, toggle.OffColor = "#00ff00", "#ff0000"
+			for _, ev := range tt.events {
+				toggle.Block().OnClick(ev)
+			}
+			if toggle.On() != tt.want {
+				t.Errorf("On() = %v, want %v", toggle.On(), tt.want)
+			}
+			if !reflect.DeepEqual(changes, tt.changes) {
+				t.Errorf("got changes %v, want %v", changes, tt.changes)
+			}
+
+			b := toggle.Block()
+			text, color := "off", Color("#ff0000")
+			if tt.want {
+				text, color = "on", "#00ff00"
+			}
+			if b.FullText != text || b.Color != color {
+				t.Errorf("got block %q %q, want %q %q", b.FullText, b.Color, text, color)
+			}
+		})
+	}
+}
+
+func TestTogglePersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dnd")
+	state := func() string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	// a missing file keeps the initial state
+	toggle := NewToggle("dnd", true, nil)
+	if err := toggle.Persist(path); err != nil {
+		t.Fatal(err)
+	}
+	toggle.Handle(ClickEvent{Button: LeftClick})
+	if got := state(); got != "off\n" {
+		t.Errorf("persisted %q, want off", got)
+	}
+	if err := toggle.Set(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := state(); got != "on\n" {
+		t.Errorf("persisted %q, want on", got)
+	}
+
+	// the state is restored from the file
+	restored := NewToggle("dnd", false, nil)
+	if err := restored.Persist(path); err != nil {
+		t.Fatal(err)
+	}
+	if !restored.On() {
+		t.Error("state not restored")
+	}
+
+	if err := os.WriteFile(path, []byte("maybe\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewToggle("dnd", false, nil).Persist(path); err == nil {
+		t.Error("restored unknown state")
+	}
+
+	// errors persisting the state are reported
+	var reported error
+	broken := NewToggle("dnd", false, nil)
+	broken.OnError = func(err error) { reported = err }
+	if err := broken.Persist(filepath.Join(t.TempDir(), "missing", "dnd")); err != nil {
+		t.Fatal(err)
+	}
+	broken.Handle(ClickEvent{Button: LeftClick})
+	if reported == nil || !broken.On() {
+		t.Errorf("got error %v and state %v, want error and on", reported, broken.On())
+	}
+}
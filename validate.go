@@ -0,0 +1,203 @@
+package i3bar
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValidationError describes a single field of a Block violating the protocol.
+type ValidationError struct {
+	// Field is the json name of the invalid field.
+	Field string
+
+	// Reason why the field is invalid.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationErrors contains all protocol violations of a Block.
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid block: " + strings.Join(msgs, ", ")
+}
+
+// Validate checks the Block against the protocol constraints.
+// If the Block is invalid, ValidationErrors describing all
+// invalid fields are returned.
+func (b *Block) Validate() error {
+	if errs := b.validate(false, true); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate collects all protocol violations of the Block.
+// In strict mode, constructs i3bar silently ignores or renders
+// wrong are reported as well. alpha reports whether the target
+// bar supports colors with alpha channel.
+func (b *Block) validate(strict, alpha bool) ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field, reason string) {
+		errs = append(errs, &ValidationError{Field: field, Reason: reason})
+	}
+
+	if b.FullText == "" && b.LazyText == nil {
+		invalid("full_text", "must not be empty")
+	}
+	colors := []struct {
+		field string
+		color Color
+	}{
+		{"color", b.Color},
+		{"background", b.Background},
+		{"border", b.Border},
+	}
+	for _, c := range colors {
+		if err := c.color.Validate(); err != nil {
+			invalid(c.field, err.Error())
+		} else if strict && !alpha && c.color.HasAlpha() {
+			invalid(c.field, "alpha channel is not supported by i3bar")
+		}
+	}
+	borders := []struct {
+		field string
+		width *int
+	}{
+		{"border_top", b.BorderTop},
+		{"border_right", b.BorderRight},
+		{"border_bottom", b.BorderBottom},
+		{"border_left", b.BorderLeft},
+	}
+	for _, border := range borders {
+		if border.width != nil && *border.width < 0 {
+			invalid(border.field, "must not be negative")
+		}
+	}
+	if b.MinWidth != nil && b.MinWidth.Text == "" && b.MinWidth.Pixels < 0 {
+		invalid("min_width", "must not be negative")
+	}
+	if _, err := b.Align.MarshalText(); err != nil {
+		invalid("align", err.Error())
+	}
+	if b.SeparatorBlockWidth < 0 {
+		invalid("separator_block_width", "must not be negative")
+	}
+	if _, err := b.Markup.MarshalText(); err != nil {
+		invalid("markup", err.Error())
+	}
+	// lazy texts are not evaluated for validation
+	if b.Markup != Pango {
+		if hasPangoTag(b.FullText) {
+			invalid("full_text", "contains pango markup but markup is not pango")
+		}
+		if hasPangoTag(b.ShortText) {
+			invalid("short_text", "contains pango markup but markup is not pango")
+		}
+	} else {
+		for _, err := range lintPango(b.FullText) {
+			invalid("full_text", err.Error())
+		}
+		for _, err := range lintPango(b.ShortText) {
+			invalid("short_text", err.Error())
+		}
+	}
+
+	if strict && len(b.Extra) > 0 {
+		// extra keys are only dropped if the regular field is encoded
+		encoded := map[string]json.RawMessage{}
+		if data, err := json.Marshal(block(*b)); err == nil {
+			json.Unmarshal(data, &encoded)
+		}
+		for key := range b.Extra {
+			if _, ok := encoded[key]; ok {
+				invalid(key, "extra key is shadowed by regular field")
+			}
+		}
+	}
+
+	return errs
+}
+
+// InvalidBlockError describes an invalid block within a StatusLine.
+type InvalidBlockError struct {
+	// Index of the block within the StatusLine.
+	Index int
+
+	// Name of the block.
+	Name string
+
+	// Instance of the block.
+	Instance string
+
+	// Err describes why the block is invalid.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *InvalidBlockError) Error() string {
+	id := e.Name
+	if e.Instance != "" {
+		id += "/" + e.Instance
+	}
+	return fmt.Sprintf("block %d (%s): %v", e.Index, id, e.Err)
+}
+
+// Unwrap returns the reason why the block is invalid.
+func (e *InvalidBlockError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidLineError contains all invalid blocks of a StatusLine.
+type InvalidLineError []*InvalidBlockError
+
+// Error implements the error interface.
+func (e InvalidLineError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid status line: " + strings.Join(msgs, "; ")
+}
+
+// Validate checks all blocks of the StatusLine against the protocol constraints.
+// If any block is invalid, an InvalidLineError describing all
+// invalid blocks is returned.
+func (l StatusLine) Validate() error {
+	return l.validate(false, true)
+}
+
+// validate checks all blocks of the StatusLine, see Block.validate.
+func (l StatusLine) validate(strict, alpha bool) error {
+	var errs InvalidLineError
+	for i, b := range l {
+		if b == nil {
+			errs = append(errs, &InvalidBlockError{Index: i, Err: errors.New("must not be nil")})
+			continue
+		}
+		if verrs := b.validate(strict, alpha); len(verrs) > 0 {
+			errs = append(errs, &InvalidBlockError{
+				Index:    i,
+				Name:     b.Name,
+				Instance: b.Instance,
+				Err:      verrs,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package i3bar
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// invalidFields returns the fields of errs.
+func invalidFields(errs ValidationErrors) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestBlockValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		block  Block
+		strict bool
+		alpha  bool
+		fields []string
+	}{
+		{name: "valid", block: Block{FullText: "a", Color: "#ff0000", BorderTop: Int(0), MinWidth: MinWidthPixels(0)}},
+		{name: "lazy text", block: Block{LazyText: TextFunc(func() string { return "<b" })}},
+		{name: "empty text", block: Block{}, fields: []string{"full_text"}},
+		{name: "invalid colors", block: Block{FullText: "a", Color: "#12", Background: "nope", Border: "#ff0000"}, fields: []string{"color", "background"}},
+		{name: "negative widths", block: Block{FullText: "a", BorderLeft: Int(-1), SeparatorBlockWidth: -1, MinWidth: MinWidthPixels(-1)}, fields: []string{"border_left", "min_width", "separator_block_width"}},
+		{name: "min width text", block: Block{FullText: "a", MinWidth: &MinWidth{Pixels: -1, Text: "abc"}}},
+		{name: "unknown align and markup", block: Block{FullText: "a", Align: Alignment(42), Markup: Markup(42)}, fields: []string{"align", "markup"}},
+		{name: "markup without pango", block: Block{FullText: "<b>a</b>", ShortText: "<i>a</i>"}, fields: []string{"full_text", "short_text"}},
+		{name: "invalid pango", block: Block{FullText: "a & b", Markup: Pango}, fields: []string{"full_text"}},
+		{name: "valid pango", block: Block{FullText: "<b>a</b> &amp; b", Markup: Pango}},
+
+		// strict mode only
+		{name: "alpha", block: Block{FullText: "a", Color: "#ff000080"}},
+		{name: "strict alpha", block: Block{FullText: "a", Color: "#ff000080"}, strict: true, fields: []string{"color"}},
+		{name: "strict alpha on swaybar", block: Block{FullText: "a", Color: "#ff000080"}, strict: true, alpha: true},
+		{name: "shadowed extra key", block: Block{FullText: "a", Extra: map[string]interface{}{"full_text": "b", "urgent": true}}},
+		// urgent is omitted and therefore not shadowed
+		{name: "strict shadowed extra key", block: Block{FullText: "a", Extra: map[string]interface{}{"full_text": "b", "urgent": true}}, strict: true, fields: []string{"full_text"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := invalidFields(tt.block.validate(tt.strict, tt.alpha))
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("invalid fields %v, want %v", got, tt.fields)
+			}
+			if !tt.strict {
+				if err := tt.block.Validate(); (err != nil) != (len(tt.fields) > 0) {
+					t.Errorf("Validate() = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusLineValidate(t *testing.T) {
+	if err := (StatusLine{{FullText: "a"}, {FullText: "b"}}).Validate(); err != nil {
+		t.Errorf("valid status line: %v", err)
+	}
+
+	err := StatusLine{{FullText: "a"}, nil, {Name: "cpu", Instance: "0", Color: "invalid"}}.Validate()
+	var lerr InvalidLineError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("got %v, want InvalidLineError", err)
+	}
+	if len(lerr) != 2 || lerr[0].Index != 1 || lerr[1].Index != 2 || lerr[1].Name != "cpu" || lerr[1].Instance != "0" {
+		t.Fatalf("got invalid blocks %v", lerr)
+	}
+	var verrs ValidationErrors
+	if !errors.As(lerr[1].Err, &verrs) || !reflect.DeepEqual(invalidFields(verrs), []string{"full_text", "color"}) {
+		t.Errorf("got block error %v", lerr[1].Err)
+	}
+	if want := "block 2 (cpu/0): invalid block: full_text: must not be empty, color: invalid color: invalid"; lerr[1].Error() != want {
+		t.Errorf("got %q, want %q", lerr[1].Error(), want)
+	}
+}
+
+func TestStreamValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		line StatusLine
+		// invalid status lines are rejected with an InvalidLineError
+		invalid bool
+	}{
+		{"valid", []Option{WithValidation()}, StatusLine{{FullText: "a"}}, false},
+		{"invalid", []Option{WithValidation()}, StatusLine{{FullText: "a", Color: "invalid"}}, true},
+		{"empty text", []Option{WithValidation()}, StatusLine{{}}, true},
+		{"alpha", []Option{WithValidation()}, StatusLine{{FullText: "a", Color: "#ff000080"}}, false},
+		{"strict alpha", []Option{WithStrict()}, StatusLine{{FullText: "a", Color: "#ff000080"}}, true},
+		{"strict alpha on swaybar", []Option{WithStrict(), WithTarget(Swaybar)}, StatusLine{{FullText: "a", Color: "#ff000080"}}, false},
+		{"disabled", nil, StatusLine{{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewStream(&bytes.Buffer{}, nil, false, DefaultHeader(), tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.Close()
+			err = s.SendLine(tt.line)
+			if !tt.invalid {
+				if err != nil {
+					t.Errorf("SendLine failed: %v", err)
+				}
+				return
+			}
+			var lerr InvalidLineError
+			if !errors.Is(err, ErrEncode) || !errors.As(err, &lerr) {
+				t.Errorf("got error %v, want ErrEncode with InvalidLineError", err)
+			}
+		})
+	}
+}
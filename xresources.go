@@ -0,0 +1,139 @@
+package i3bar
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadXresources reads X resources as found in ~/.Xresources or
+// the output of xrdb -query from r and creates a Theme.
+//
+// The colors are mapped as follows:
+// background and foreground are used as is, color8 is the border,
+// color1 (red) is bad, color3 (yellow) is degraded and color2 (green) is good.
+// Urgent blocks use the background color on color1.
+//
+// Resources for all applications (e.g. *.color0) take precedence over
+// application specific resources (e.g. URxvt.color0).
+func LoadXresources(r io.Reader) (*Theme, error) {
+	resources, err := parseXresources(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read X resources")
+	}
+
+	colors := map[string]Color{}
+	for _, key := range []string{"background", "foreground", "color1", "color2", "color3", "color8"} {
+		value, ok := resources[key]
+		if !ok {
+			return nil, errors.Errorf("X resources are missing %s", key)
+		}
+		c, err := ParseColor(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid X resource color %s", key)
+		}
+		colors[key] = c
+	}
+
+	return &Theme{
+		Foreground:       colors["foreground"],
+		Background:       colors["background"],
+		Border:           colors["color8"],
+		UrgentForeground: colors["background"],
+		UrgentBackground: colors["color1"],
+		Good:             colors["color2"],
+		Degraded:         colors["color3"],
+		Bad:              colors["color1"],
+	}, nil
+}
+
+// LoadXresourcesFile reads X resources from the file at path and creates a Theme.
+func LoadXresourcesFile(path string) (*Theme, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open X resources")
+	}
+	defer f.Close()
+	return LoadXresources(f)
+}
+
+// LoadXrdb queries the X resources of the running X server
+// using xrdb and creates a Theme.
+func LoadXrdb() (*Theme, error) {
+	out, err := exec.Command("xrdb", "-query").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to query X resources")
+	}
+	return LoadXresources(bytes.NewReader(out))
+}
+
+// parseXresources parses the color resources color0 to color15,
+// foreground and background. Simple #define macros are expanded.
+func parseXresources(r io.Reader) (map[string]string, error) {
+	defines := map[string]string{}
+	resources := map[string]string{}
+	generic := map[string]bool{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			if len(fields) == 3 && fields[0] == "#define" {
+				defines[fields[1]] = fields[2]
+			}
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if v, ok := defines[value]; ok {
+			value = v
+		}
+
+		// the resource is the last component of the name
+		i := strings.LastIndexAny(name, ".*")
+		key := strings.ToLower(name[i+1:])
+		if !isColorResource(key) {
+			continue
+		}
+		isGeneric := i == 0 || name[:i] == "*"
+		if generic[key] && !isGeneric {
+			continue
+		}
+		resources[key] = value
+		generic[key] = isGeneric
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// isColorResource reports whether key is a terminal color resource.
+func isColorResource(key string) bool {
+	if key == "foreground" || key == "background" {
+		return true
+	}
+	if !strings.HasPrefix(key, "color") {
+		return false
+	}
+	switch key[len("color"):] {
+	case "0", "1", "2", "3", "4", "5", "6", "7",
+		"8", "9", "10", "11", "12", "13", "14", "15":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package i3bar
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const xresources = `! comment
+#define alert #ff0000
+#include "other"
+URxvt.font: xft:monospace
+URxvt.background: #000000
+*.background: #101010
+*foreground: #e0e0e0
+*.color1: alert
+*.color2: #00FF00
+URxvt.color3: #ffff00
+*color3: #808000
+XTerm*color3: #ffff80
+*.color8: #303030
+*.color16: #ffffff
+invalid line
+`
+
+var xresourcesTheme = &Theme{
+	Foreground:       "#e0e0e0",
+	Background:       "#101010",
+	Border:           "#303030",
+	UrgentForeground: "#101010",
+	UrgentBackground: "#ff0000",
+	Good:             "#00ff00",
+	Degraded:         "#808000",
+	Bad:              "#ff0000",
+}
+
+func TestLoadXresources(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources string
+		want      *Theme
+		err       string
+	}{
+		// generic resources take precedence, regardless of their position
+		{"valid", xresources, xresourcesTheme, ""},
+		{"missing color", strings.Replace(xresources, "color8", "color9", 1), nil, "missing color8"},
+		{"invalid color", strings.Replace(xresources, "#00FF00", "green!", 1), nil, "invalid X resource color color2"},
+		// named colors are parsed, but undefined macros are invalid
+		{"undefined macro", strings.Replace(xresources, "#define alert", "#define other", 1), nil, "invalid X resource color color1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadXresources(strings.NewReader(tt.resources))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadXresourcesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".Xresources")
+	if err := os.WriteFile(path, []byte(xresources), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadXresourcesFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, xresourcesTheme) {
+		t.Errorf("got %+v, want %+v", got, xresourcesTheme)
+	}
+
+	if _, err := LoadXresourcesFile(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("loading missing X resources succeeded")
+	}
+}